@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	_ "aoc-2024/day-01/go/day01"
+	"aoc-2024/internal/registry"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: aoc run -day N -part P [-input path]\n")
+}
+
+// run dispatches a single day and part to its registered solver.
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	day := fs.Int("day", 0, "puzzle day (1-25)")
+	part := fs.Int("part", 0, "puzzle part (1 or 2)")
+	input := fs.String("input", "", "path to the puzzle input file (default day-NN/puzzle_input.txt)")
+	fs.Parse(args)
+
+	solver, ok := registry.Lookup(*day, *part)
+	if !ok {
+		return fmt.Errorf("no solver registered for day %d part %d", *day, *part)
+	}
+
+	path := *input
+	if path == "" {
+		path = fmt.Sprintf("day-%02d/puzzle_input.txt", *day)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening file: %v", err)
+	}
+	defer file.Close()
+
+	start := time.Now()
+	answer, err := solver(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Day %d part %d solved in %v\n", *day, *part, time.Since(start))
+	fmt.Println(answer)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "run":
+		if err := run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
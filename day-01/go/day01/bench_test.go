@@ -0,0 +1,125 @@
+package day01
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+// benchLines is the number of lines in the generated benchmark input.
+const benchLines = 100_000
+
+// benchInput generates the benchmark input once per test binary, so setup is
+// not timed and every benchmark reads the same data.
+var benchInput = sync.OnceValue(func() []byte {
+	var buf bytes.Buffer
+	if err := Generate(&buf, benchLines, 1); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+})
+
+func BenchmarkSimilarityScore(b *testing.B) {
+	data := benchInput()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cols, err := ReadColumns(bytes.NewReader(data), ParseOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		SimilarityScore(cols.Left, cols.Right)
+	}
+}
+
+// unsized hides the size of a reader, as stdin does, so parsing falls back to
+// the fixed capacity hint.
+type unsized struct{ io.Reader }
+
+func BenchmarkCapacityHint(b *testing.B) {
+	data := benchInput()
+	tests := []struct {
+		name  string
+		input func() io.Reader
+	}{
+		{name: "adaptive", input: func() io.Reader { return bytes.NewReader(data) }},
+		{name: "fixed", input: func() io.Reader { return unsized{bytes.NewReader(data)} }},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Solve(tt.input(), ParseOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRepeatedSolve(b *testing.B) {
+	// Both cases sort once through SolveBoth; only the buffer reuse differs
+	data := benchInput()
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := Solve(bytes.NewReader(data), ParseOptions{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("reused", func(b *testing.B) {
+		solver := NewSolver(ParseOptions{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := solver.Solve(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkSolveBoth(b *testing.B) {
+	cols, err := ReadColumns(bytes.NewReader(benchInput()), ParseOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("separate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			TotalDistance(cols.Left, cols.Right)
+			SimilarityScore(cols.Left, cols.Right)
+		}
+	})
+	b.Run("shared sort", func(b *testing.B) {
+		left, right := make([]int64, len(cols.Left)), make([]int64, len(cols.Right))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// SolveBoth sorts in place, so start each run from the unsorted columns
+			copy(left, cols.Left)
+			copy(right, cols.Right)
+			SolveBoth(left, right)
+		}
+	})
+}
+
+func BenchmarkMmap(b *testing.B) {
+	path := writeInput(b, string(benchInput()))
+	for _, mmap := range []bool{false, true} {
+		name := "buffered"
+		if mmap {
+			name = "mmap"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(benchInput())))
+			for i := 0; i < b.N; i++ {
+				if _, err := SolveFile(path, ParseOptions{Mmap: mmap}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package day01
+
+import (
+	"context"
+	"io"
+)
+
+// CheckReport describes the shape of an input without solving it.
+type CheckReport struct {
+	Lines    int
+	Skipped  int
+	Comments int
+	Headers  int
+
+	LeftCount, RightCount int
+	LeftMin, LeftMax      int64
+	RightMin, RightMax    int64
+}
+
+// Balanced reports whether both columns hold the same number of values.
+func (c *CheckReport) Balanced() bool {
+	return c.LeftCount == c.RightCount
+}
+
+// Clean reports whether the input would parse in strict mode.
+func (c *CheckReport) Clean() bool {
+	return c.Skipped == 0 && c.Balanced()
+}
+
+// Check parses r leniently and summarizes it. Malformed lines and unbalanced
+// columns are reported rather than returned as errors; only read failures and
+// NonNegative violations are errors.
+func Check(r io.Reader, opts ParseOptions) (*CheckReport, error) {
+	opts.Strict = false
+	cols, err := scanColumns(context.Background(), r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CheckReport{
+		Lines:      cols.Lines,
+		Skipped:    cols.Skipped,
+		Comments:   cols.Comments,
+		Headers:    cols.Headers,
+		LeftCount:  len(cols.Left),
+		RightCount: len(cols.Right),
+	}
+	report.LeftMin, report.LeftMax = bounds(cols.Left)
+	report.RightMin, report.RightMax = bounds(cols.Right)
+	return report, nil
+}
+
+// CheckFile is Check for a named input, with the same handling of "-" and gzip
+// as ParseColumns.
+func CheckFile(filename string, opts ParseOptions) (*CheckReport, error) {
+	src, err := openSource(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return Check(src, opts)
+}
+
+// bounds returns the smallest and largest value, or zeros for an empty list.
+func bounds(values []int64) (int64, int64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	return lo, hi
+}
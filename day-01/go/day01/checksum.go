@@ -0,0 +1,36 @@
+package day01
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+)
+
+// Checksum fingerprints the input as the FNV-64a hash of its (left, right) pairs
+// in sorted order, so any reordering of the lines gives the same checksum while a
+// changed value almost surely does not. Both slices are only read, and must be
+// paired by line as parsed, before anything sorts them. Weights are not included.
+// Time Complexity: O(n log n) dominated by sorting
+// Space Complexity: O(n) for the sorted pairs
+func Checksum(left, right []int64) uint64 {
+	pairs := make([][2]int64, min(len(left), len(right)))
+	for i := range pairs {
+		pairs[i] = [2]int64{left[i], right[i]}
+	}
+	slices.SortFunc(pairs, func(a, b [2]int64) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+
+	h := fnv.New64a()
+	var buf [16]byte
+	for _, p := range pairs {
+		binary.LittleEndian.PutUint64(buf[:8], uint64(p[0]))
+		binary.LittleEndian.PutUint64(buf[8:], uint64(p[1]))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
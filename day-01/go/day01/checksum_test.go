@@ -0,0 +1,42 @@
+package day01
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	left, right := randomColumns(5, 200, 50)
+	origLeft, origRight := slices.Clone(left), slices.Clone(right)
+	want := Checksum(left, right)
+	if !slices.Equal(left, origLeft) || !slices.Equal(right, origRight) {
+		t.Fatal("Checksum modified its input")
+	}
+
+	// Reordering whole lines keeps the checksum
+	rng := rand.New(rand.NewSource(6))
+	shuffledLeft, shuffledRight := slices.Clone(left), slices.Clone(right)
+	rng.Shuffle(len(shuffledLeft), func(i, j int) {
+		shuffledLeft[i], shuffledLeft[j] = shuffledLeft[j], shuffledLeft[i]
+		shuffledRight[i], shuffledRight[j] = shuffledRight[j], shuffledRight[i]
+	})
+	if got := Checksum(shuffledLeft, shuffledRight); got != want {
+		t.Errorf("reordered checksum = %016x, want %016x", got, want)
+	}
+
+	// A single changed value, or values moved between lines, changes it
+	changed := slices.Clone(right)
+	changed[100]++
+	if Checksum(left, changed) == want {
+		t.Error("checksum unchanged after a value changed")
+	}
+	swapped := slices.Clone(right)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	if right[0] != right[1] && Checksum(left, swapped) == want {
+		t.Error("checksum unchanged after pairing right values with other lines")
+	}
+	if Checksum(right, left) == want {
+		t.Error("checksum unchanged with the columns exchanged")
+	}
+}
@@ -0,0 +1,816 @@
+// Package day01 solves Advent of Code 2024 day 1, Historian Hysteria.
+//
+// ReadColumns parses the two lists from any io.Reader, and the scoring functions
+// take the parsed columns. Solve and SolveFile parse and compute both answers in
+// one call.
+package day01
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"aoc-2024/internal/input"
+)
+
+// ParseOptions controls how input lines are parsed.
+type ParseOptions struct {
+	// Strict aborts on the first malformed line instead of skipping it.
+	Strict bool
+	// Delimiter splits fields on an exact character; empty means any whitespace.
+	Delimiter string
+	// Workers parses file input in parallel chunks when greater than 1.
+	// Compressed input is always read sequentially.
+	Workers int
+	// Gzip decompresses the input regardless of its file name.
+	Gzip bool
+	// NonNegative rejects negative values with an error naming the line, even
+	// when Strict is off.
+	NonNegative bool
+	// Comment, when non-zero, marks lines whose first non-whitespace character
+	// it is as comments to ignore; they are neither parsed nor counted as skipped.
+	Comment rune
+	// Clean strips digit-grouping underscores and commas, as in 1_000 or 1,000,
+	// from each value before parsing. It cannot be combined with a comma Delimiter.
+	Clean bool
+	// SkipLines ignores the first lines of each input and HeadLines, when
+	// non-zero, stops after that many more, so only the window between them is
+	// solved. A windowed input is always read sequentially.
+	SkipLines int
+	HeadLines int
+	// FieldIndices, when set, holds the zero-based indices of the left and right
+	// fields, and lines may carry any number of other fields. When nil every line
+	// must hold exactly two fields.
+	FieldIndices []int
+	// MaxValue, when non-zero, rejects lines holding a value greater than it. A
+	// strict parse fails on such a line; otherwise it is skipped and counted in
+	// SkipCounts.OutOfRange.
+	MaxValue int64
+	// SkipSamples is how many skipped lines, at most, to keep in
+	// Columns.SkippedLines. Zero keeps none.
+	SkipSamples int
+	// Weighted reads a third integer field on every line as that line's weight
+	// for WeightedSimilarityScore. It cannot be combined with FieldIndices.
+	Weighted bool
+	// Timestamped reads a third integer field on every line as a unix timestamp
+	// and ignores lines stamped before Since, like comments, so only recent pairs
+	// are solved. It cannot be combined with Weighted or FieldIndices.
+	Timestamped bool
+	Since       int64
+	// Retries is how many more times to try opening a file or URL input, with a
+	// short growing backoff, when opening fails transiently: a network error or
+	// timeout, a server error, or a connection dropped before the first byte. A
+	// missing or unreadable input, a directory or a client error fails at once,
+	// and errors once reading has begun are never retried.
+	Retries int
+	// Base is the radix values are written in: 2, 8, 10 or 16, where hexadecimal
+	// values may carry a 0x prefix. Zero means 10.
+	Base int
+	// Timeout bounds fetching an http:// or https:// input, reading the body
+	// included; zero means no limit.
+	Timeout time.Duration
+	// Pad evens out columns of different lengths, usually left by lines with only
+	// one valid value, by appending PadValue to the shorter one instead of failing.
+	// It cannot be combined with Weighted.
+	Pad      bool
+	PadValue int64
+	// SkipLine, when set, is called with the line number and text, without its
+	// line ending, of every line before it is parsed. Lines it returns true for
+	// are ignored like comments, even when Strict is set. Input is then read
+	// sequentially so line numbers count from the start of the input and calls
+	// are never concurrent.
+	SkipLine func(lineNum int, raw string) bool
+	// Mmap reads a regular file through a read-only memory mapping instead of
+	// buffered reads. Inputs that cannot be mapped, such as pipes, compressed or
+	// transcoded input, are read the usual way. A parallel parse takes precedence.
+	Mmap bool
+	// Progress, when set, is updated as lines are read so another goroutine can
+	// report on a long parse.
+	Progress *Progress
+
+	// continued marks a parallel chunk after the first, whose first line is not
+	// the first line of the input.
+	continued bool
+}
+
+// thirdField names the value read from the third field of every line, or is
+// empty when lines hold only the two values.
+func (o ParseOptions) thirdField() string {
+	switch {
+	case o.Weighted:
+		return "weight"
+	case o.Timestamped:
+		return "timestamp"
+	}
+	return ""
+}
+
+// windowed reports whether only part of each input is read.
+func (o ParseOptions) windowed() bool {
+	return o.SkipLines > 0 || o.HeadLines > 0
+}
+
+// Validate reports an options combination that cannot be parsed unambiguously.
+func (o ParseOptions) Validate() error {
+	if o.Clean && o.Delimiter == "," {
+		return errors.New("cleaning commas from values conflicts with the comma delimiter")
+	}
+	if o.SkipLines < 0 || o.HeadLines < 0 {
+		return errors.New("line window bounds must not be negative")
+	}
+	if o.FieldIndices != nil && (len(o.FieldIndices) != 2 || o.FieldIndices[0] < 0 || o.FieldIndices[1] < 0) {
+		return fmt.Errorf("field indices must be two non-negative indices, got %v", o.FieldIndices)
+	}
+	if o.Weighted && o.FieldIndices != nil {
+		return errors.New("weighted input reads the third field and cannot be combined with field indices")
+	}
+	if o.Timestamped && (o.Weighted || o.FieldIndices != nil) {
+		return errors.New("timestamped input reads the third field and cannot be combined with weighted input or field indices")
+	}
+	if o.Weighted && o.Pad {
+		return errors.New("padded values have no weight, so padding cannot be combined with weighted input")
+	}
+	switch o.Base {
+	case 0, 2, 8, 10, 16:
+	default:
+		return fmt.Errorf("unsupported base %d: use 2, 8, 10 or 16", o.Base)
+	}
+	return nil
+}
+
+// ParseColumns reads the puzzle input from filename, from stdin when filename is
+// "-", or from an http:// or https:// URL, and returns the left and right
+// columns. Input named *.gz, or any input when opts.Gzip is set, is decompressed
+// while reading. Errors return partial columns as ReadColumns does, and nil
+// columns if the input could not be opened.
+func ParseColumns(filename string, opts ParseOptions) (*Columns, error) {
+	return ParseColumnsContext(context.Background(), filename, opts)
+}
+
+// ParseColumnsContext is ParseColumns stopping once ctx is done. Parsing stops at
+// a line boundary, returning the columns parsed so far with ctx.Err().
+func ParseColumnsContext(ctx context.Context, filename string, opts ParseOptions) (*Columns, error) {
+	src, err := openSource(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// Chunked parsing needs random access, so pipes and other non-regular
+	// files fall back to a single sequential pass.
+	if opts.Workers > 1 && src.file != nil && sizeOf(src.file) >= 0 && !opts.windowed() && opts.SkipLine == nil {
+		return readColumnsParallel(ctx, src.file, opts)
+	}
+	if opts.Mmap && src.file != nil {
+		if data, unmap, err := mapFile(src.file); err == nil {
+			defer unmap()
+			return readLines(ctx, &mappedLines{data: data}, int64(len(data)), opts)
+		}
+		// Fall back to buffered reading when the file cannot be mapped
+	}
+	return readColumnsContext(ctx, src, opts)
+}
+
+// Columns holds the parsed left and right values along with line accounting.
+type Columns struct {
+	Left, Right []int64
+	// Lines is the number of input lines read, including skipped ones. Lines
+	// before a SkipLines window are not included.
+	Lines int
+	// Before is the number of lines ignored ahead of a SkipLines window.
+	Before int
+	// Skipped is the number of malformed lines left out of the columns.
+	Skipped int
+	// Skips breaks Skipped down by what was wrong with each line.
+	Skips SkipCounts
+	// Comments is the number of comment lines ignored, including lines rejected by
+	// ParseOptions.SkipLine.
+	Comments int
+	// Headers is the number of apparent header rows ignored, such as "left right"
+	// on the first line: at most one per input.
+	Headers int
+	// SkippedLines holds the first ParseOptions.SkipSamples skipped lines, in
+	// input order. Blank lines are counted as skipped but not kept.
+	SkippedLines []SkippedLine
+	// Padded is the number of ParseOptions.PadValue values appended to the
+	// shorter column.
+	Padded int
+	// Weights holds the per-line weights read in Weighted mode, in input order
+	// like Left as parsed; it is nil otherwise.
+	Weights []int64
+
+	// leftDescends and rightDescends record whether a value was ever smaller
+	// than the one parsed before it.
+	leftDescends, rightDescends bool
+}
+
+// LeftSorted reports whether the left values arrived in ascending order. It
+// describes the input, and stays the same after the columns are sorted.
+func (c *Columns) LeftSorted() bool {
+	return !c.leftDescends
+}
+
+// RightSorted reports whether the right values arrived in ascending order. It
+// describes the input, and stays the same after the columns are sorted.
+func (c *Columns) RightSorted() bool {
+	return !c.rightDescends
+}
+
+// Swap exchanges the left and right columns, for checking which way round an
+// input was meant to be read. Neither puzzle answer changes: the similarity score
+// is the sum of x·countLeft(x)·countRight(x) over every value x, which is
+// symmetric. The weighted score, global window scores and group sums do depend on
+// the assignment. Per-line weights stay with their lines.
+func (c *Columns) Swap() {
+	c.Left, c.Right = c.Right, c.Left
+	c.leftDescends, c.rightDescends = c.rightDescends, c.leftDescends
+}
+
+// appendLeft appends v to the left column, noting whether it breaks ascending order.
+func (c *Columns) appendLeft(v int64) {
+	if n := len(c.Left); n > 0 && v < c.Left[n-1] {
+		c.leftDescends = true
+	}
+	c.Left = append(c.Left, v)
+}
+
+// appendRight appends v to the right column, noting whether it breaks ascending order.
+func (c *Columns) appendRight(v int64) {
+	if n := len(c.Right); n > 0 && v < c.Right[n-1] {
+		c.rightDescends = true
+	}
+	c.Right = append(c.Right, v)
+}
+
+// SkippedLine is one malformed line left out of the columns.
+type SkippedLine struct {
+	// Line is the one-based line number in the input.
+	Line int
+	// Text is the raw line.
+	Text string
+	// Err says what was wrong with it.
+	Err error
+}
+
+// SkipCounts categorizes skipped lines; its fields sum to Columns.Skipped.
+type SkipCounts struct {
+	// Blank lines are empty or hold only whitespace.
+	Blank int
+	// FieldCount lines hold the wrong number of fields: not exactly two, or too
+	// few for the selected FieldIndices.
+	FieldCount int
+	// NotInteger lines hold a left or right field that is not an integer.
+	NotInteger int
+	// OutOfRange lines hold a value above ParseOptions.MaxValue.
+	OutOfRange int
+}
+
+// add counts one skipped line by the parse error that rejected it.
+func (s *SkipCounts) add(err error) {
+	fieldErr, ok := err.(*fieldCountError)
+	_, tooLarge := err.(*rangeError)
+	switch {
+	case ok && fieldErr.got == 0:
+		s.Blank++
+	case ok:
+		s.FieldCount++
+	case tooLarge:
+		s.OutOfRange++
+	default:
+		s.NotInteger++
+	}
+}
+
+// MergeColumns concatenates parsed columns in order, summing their line accounting.
+func MergeColumns(parts ...*Columns) *Columns {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	leftTotal, rightTotal := 0, 0
+	for _, part := range parts {
+		leftTotal += len(part.Left)
+		rightTotal += len(part.Right)
+	}
+	merged := &Columns{
+		Left:  make([]int64, 0, leftTotal),
+		Right: make([]int64, 0, rightTotal),
+	}
+	for _, part := range parts {
+		// Each part keeps the order only if it starts where the previous one ended
+		merged.leftDescends = merged.leftDescends || part.leftDescends || descendsAt(merged.Left, part.Left)
+		merged.rightDescends = merged.rightDescends || part.rightDescends || descendsAt(merged.Right, part.Right)
+		merged.Left = append(merged.Left, part.Left...)
+		merged.Right = append(merged.Right, part.Right...)
+		merged.Weights = append(merged.Weights, part.Weights...)
+		merged.Lines += part.Lines
+		merged.Before += part.Before
+		merged.Skipped += part.Skipped
+		merged.Skips.Blank += part.Skips.Blank
+		merged.Skips.FieldCount += part.Skips.FieldCount
+		merged.Skips.NotInteger += part.Skips.NotInteger
+		merged.Skips.OutOfRange += part.Skips.OutOfRange
+		merged.SkippedLines = append(merged.SkippedLines, part.SkippedLines...)
+		merged.Comments += part.Comments
+		merged.Padded += part.Padded
+		merged.Headers += part.Headers
+	}
+	return merged
+}
+
+// descendsAt reports whether appending next to prev puts a smaller value after a
+// larger one at the seam.
+func descendsAt(prev, next []int64) bool {
+	return len(prev) > 0 && len(next) > 0 && next[0] < prev[len(prev)-1]
+}
+
+// keepPartial appends the surviving value of a half-parsed line to its column.
+func (c *Columns) keepPartial(err error) {
+	partial, ok := err.(*partialPairError)
+	if !ok {
+		return
+	}
+	if partial.leftOK {
+		c.appendLeft(partial.value)
+	} else {
+		c.appendRight(partial.value)
+	}
+}
+
+// errUTF16 reports UTF-16 input read without the transcoding openSource applies.
+var errUTF16 = errors.New("input appears to be UTF-16, please convert it to UTF-8")
+
+// ErrParse matches, via errors.Is, every error caused by malformed input content.
+var ErrParse = errors.New("parse error")
+
+// checkBalanced returns an error when the columns hold different numbers of values.
+func checkBalanced(left, right []int64) error {
+	if len(left) != len(right) {
+		return &unbalancedError{left: len(left), right: len(right)}
+	}
+	return nil
+}
+
+// validate returns the error for parsed columns that cannot be solved: columns of
+// different lengths, unless opts.Pad evens them out, or input whose every line had
+// the wrong number of fields.
+func (c *Columns) validate(opts ParseOptions) error {
+	if err := c.checkShape(opts); err != nil {
+		return err
+	}
+	if opts.Pad {
+		c.pad(opts.PadValue)
+	}
+	return checkBalanced(c.Left, c.Right)
+}
+
+// pad appends value to the shorter column until both are the same length.
+func (c *Columns) pad(value int64) {
+	for len(c.Left) < len(c.Right) {
+		c.appendLeft(value)
+		c.Padded++
+	}
+	for len(c.Right) < len(c.Left) {
+		c.appendRight(value)
+		c.Padded++
+	}
+}
+
+// checkShape returns an error when the input held lines but none of them had the
+// expected number of fields, as when a one-column file is read. Skipping each line
+// would otherwise leave both answers at 0 with no explanation.
+func (c *Columns) checkShape(opts ParseOptions) error {
+	content := c.Lines - c.Comments - c.Headers - c.Skips.Blank
+	if content == 0 || c.Skips.FieldCount < content {
+		return nil
+	}
+	if opts.FieldIndices != nil {
+		return &shapeError{want: max(opts.FieldIndices[0], opts.FieldIndices[1]) + 1, atLeast: true}
+	}
+	if opts.thirdField() != "" {
+		return &shapeError{want: 3}
+	}
+	return &shapeError{want: 2}
+}
+
+// shapeError reports input in which no line had the expected number of fields.
+type shapeError struct {
+	want int
+	// atLeast is set when fields are selected by index, as for fieldCountError.
+	atLeast bool
+}
+
+func (e *shapeError) Error() string {
+	if e.atLeast {
+		return fmt.Sprintf("no lines with at least %d fields found — is this the right input format?", e.want)
+	}
+	columns := "two"
+	if e.want == 3 {
+		columns = "three"
+	}
+	return fmt.Sprintf("no %s-column lines found — is this the right input format?", columns)
+}
+
+func (e *shapeError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// unbalancedError reports columns of different lengths.
+type unbalancedError struct {
+	left, right int
+}
+
+func (e *unbalancedError) Error() string {
+	return fmt.Sprintf("unbalanced columns: %d left values, %d right values", e.left, e.right)
+}
+
+func (e *unbalancedError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// lineError identifies the malformed line that aborted a strict parse.
+type lineError struct {
+	line int
+	err  error
+	// located is set when text holds the line, without its line ending, and
+	// column the byte offset in it of the token that failed to parse.
+	located bool
+	text    string
+	column  int
+}
+
+// LineContext locates a parse error within its input line.
+type LineContext struct {
+	Line int
+	Text string
+	// Column is the byte offset in Text of the token that failed to parse, or
+	// of the end of the line when a field is missing.
+	Column int
+}
+
+// ErrorContext returns the line a strict parse error stopped at along with the
+// offending token's position, or false when err does not carry the line text.
+func ErrorContext(err error) (LineContext, bool) {
+	var lineErr *lineError
+	if !errors.As(err, &lineErr) || !lineErr.located {
+		return LineContext{}, false
+	}
+	return LineContext{Line: lineErr.line, Text: lineErr.text, Column: lineErr.column}, true
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+func (e *lineError) Is(target error) bool {
+	return target == ErrParse
+}
+
+func (e *lineError) Unwrap() error {
+	return e.err
+}
+
+// ReadColumns reads the left and right columns from r in a single pass.
+// In strict mode a malformed line aborts with an error naming the line, otherwise
+// malformed lines are skipped and counted. Columns of different lengths are an error,
+// as is input in which no line has the expected number of fields. On any error
+// after reading began, the columns hold what was parsed before the failure.
+// Nothing is printed; callers decide how to report the line accounting.
+// A final line without a trailing newline is parsed like any other, and a leading
+// UTF-8 byte order mark is ignored.
+// Time Complexity: O(n) where n is the number of lines
+// Space Complexity: O(n) for both columns
+func ReadColumns(r io.Reader, opts ParseOptions) (*Columns, error) {
+	return readColumnsContext(context.Background(), r, opts)
+}
+
+const (
+	// defaultCapacity seeds the columns when the input size is unknown, e.g. stdin.
+	defaultCapacity = 1000
+	// estimatedLineLength is the typical byte length of an input line such as
+	// "15131   78158\n", used to turn a byte size into a line count estimate.
+	estimatedLineLength = 14
+)
+
+// capacityHint estimates how many lines an input of size bytes holds.
+func capacityHint(size int64) int {
+	if size <= 0 {
+		return defaultCapacity
+	}
+	return int(size/estimatedLineLength) + 1
+}
+
+// sizeOf returns the byte size of r when it is cheaply known, or -1.
+func sizeOf(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *source:
+		return sizeOf(v.Reader)
+	case interface{ Size() int64 }:
+		return v.Size()
+	case *os.File:
+		info, err := v.Stat()
+		if err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
+}
+
+// readColumnsContext is ReadColumns with cancellation checked while scanning.
+func readColumnsContext(ctx context.Context, r io.Reader, opts ParseOptions) (*Columns, error) {
+	return readLines(ctx, input.NewScanner(r), sizeOf(r), opts)
+}
+
+// readLines is readColumnsContext over any line source, size being the byte
+// length of its input or -1 when unknown.
+func readLines(ctx context.Context, lines lineScanner, size int64, opts ParseOptions) (*Columns, error) {
+	cols, err := scanLines(ctx, lines, size, opts)
+	if err != nil {
+		return cols, err
+	}
+	if err := cols.validate(opts); err != nil {
+		return cols, err
+	}
+	return cols, nil
+}
+
+// cancelCheckInterval is how many lines are scanned between context checks.
+const cancelCheckInterval = 4096
+
+// scanColumns does the line-by-line parsing behind ReadColumns, returning ctx.Err()
+// once the context is done.
+func scanColumns(ctx context.Context, r io.Reader, opts ParseOptions) (*Columns, error) {
+	return scanLines(ctx, input.NewScanner(r), sizeOf(r), opts)
+}
+
+// scanLines is scanColumns over any line source of size bytes, or -1 when unknown.
+func scanLines(ctx context.Context, lines lineScanner, size int64, opts ParseOptions) (*Columns, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Pre-allocate both columns from the estimated line count
+	hint := capacityHint(size)
+	cols := &Columns{
+		Left:  make([]int64, 0, hint),
+		Right: make([]int64, 0, hint),
+	}
+	if err := scanInto(ctx, lines, opts, cols); err != nil {
+		// Keep the lines parsed before the failure for partial results
+		return cols, err
+	}
+	return cols, nil
+}
+
+// lineScanner yields input lines without their terminators. *bufio.Scanner
+// implements it, and so does mappedLines for a memory-mapped file.
+type lineScanner interface {
+	Scan() bool
+	Bytes() []byte
+	Err() error
+}
+
+// scanInto appends the lines read by scanner to cols, so callers can supply
+// columns and a scanner buffer they reuse across inputs.
+func scanInto(ctx context.Context, scanner lineScanner, opts ParseOptions, cols *Columns) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	// Single pass: collect both columns from the same read, stopping once the
+	// HeadLines window is full
+	parser := newLineParser(opts)
+	tracker := progressTracker{progress: opts.Progress}
+	defer tracker.flush()
+	for (opts.HeadLines == 0 || cols.Lines < opts.HeadLines) && scanner.Scan() {
+		// Line numbers in errors count from the start of the input, window or not
+		lineNo := cols.Before + cols.Lines + 1
+		tracker.line(len(scanner.Bytes()))
+		if lineNo%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			tracker.flush()
+		}
+		if cols.Before < opts.SkipLines {
+			cols.Before++
+			continue
+		}
+		cols.Lines++
+
+		line := scanner.Bytes()
+		if lineNo == 1 && !opts.continued {
+			// Drop a byte order mark left by editors that write one
+			line = input.TrimBOM(line)
+			if isUTF16, _ := input.SniffUTF16(line); isUTF16 {
+				return &lineError{line: lineNo, err: errUTF16}
+			}
+			// A first line of column names is recognized even when Strict is set
+			if parser.isHeader(line) {
+				cols.Headers++
+				continue
+			}
+		}
+		if parser.isComment(line) || opts.SkipLine != nil && opts.SkipLine(lineNo, string(bytes.TrimSuffix(line, []byte{'\r'}))) {
+			cols.Comments++
+			continue
+		}
+		leftNum, rightNum, err := parser.parse(line)
+		if err != nil {
+			var missing *missingThirdError
+			if opts.Strict || errors.As(err, &missing) {
+				// The failing line ends the parse uncounted, so partial results
+				// cover only the lines before it
+				cols.Lines--
+				return parser.locate(lineNo, line, err, parser.bad)
+			}
+			cols.Skipped++
+			cols.Skips.add(err)
+			cols.keepPartial(err)
+			if len(cols.SkippedLines) < opts.SkipSamples && len(bytes.TrimSpace(line)) > 0 {
+				cols.SkippedLines = append(cols.SkippedLines, SkippedLine{Line: lineNo, Text: string(line), Err: err})
+			}
+			continue
+		}
+		if opts.Timestamped && parser.third < opts.Since {
+			cols.Comments++
+			continue
+		}
+		if opts.NonNegative && (leftNum < 0 || rightNum < 0) {
+			cols.Lines--
+			return parser.negative(lineNo, line, leftNum)
+		}
+		cols.appendLeft(leftNum)
+		cols.appendRight(rightNum)
+		if opts.Weighted {
+			cols.Weights = append(cols.Weights, parser.third)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return input.ScanError(err, cols.Before+cols.Lines+1)
+	}
+	return nil
+}
+
+// SimilarityScore sums each left number multiplied by its frequency in the right list.
+//
+// The score is exact while it fits in an int64. With n pairs of values no larger
+// than V in magnitude it is at most V·n², reached when every value is equal, so
+// 10^6 pairs of 10^6 give 10^18, below math.MaxInt64 (about 9.2·10^18). Puzzle
+// inputs, around 10^3 pairs of five-digit values, peak near 10^11. Beyond the limit
+// the sum wraps silently. TotalDistance is bounded by 2·V·n and overflows far later.
+//
+// Both slices are only read, so concurrent calls may share them.
+// Time Complexity: O(n + m) where n is length of left list, m is length of right list
+// Space Complexity: O(m) for the frequency map
+func SimilarityScore(left, right []int64) int64 {
+	return ScoreWithFrequencies(left, Frequencies(right))
+}
+
+// SimilarityScoreContext parses r and computes the similarity score, aborting
+// with ctx.Err() if the context is cancelled before the input is fully read.
+func SimilarityScoreContext(ctx context.Context, r io.Reader) (int64, error) {
+	cols, err := readColumnsContext(ctx, r, ParseOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return SimilarityScore(cols.Left, cols.Right), nil
+}
+
+// Frequencies counts how often each value occurs in the right list.
+func Frequencies(right []int64) map[int64]int64 {
+	// Initialize frequency map for right-side numbers with capacity hint
+	rightFreq := make(map[int64]int64, len(right))
+	countInto(rightFreq, right)
+	return rightFreq
+}
+
+// countInto adds the occurrences of each value in right to rightFreq.
+func countInto(rightFreq map[int64]int64, right []int64) {
+	for _, rightNum := range right {
+		rightFreq[rightNum]++
+	}
+}
+
+// ScoreWithFrequencies computes the similarity score against a prebuilt frequency map.
+func ScoreWithFrequencies(left []int64, rightFreq map[int64]int64) int64 {
+	var totalScore int64
+	for _, leftNum := range left {
+		// Multiply left number by its frequency in right list
+		totalScore += leftNum * rightFreq[leftNum]
+	}
+	return totalScore
+}
+
+// WeightedSimilarityScore is SimilarityScore with each left value's contribution
+// multiplied by its weight: the sum of weights[i] * left[i] * its frequency in
+// right. weights must be as long as left.
+// Time Complexity: O(n + m)
+// Space Complexity: O(m) for the frequency map
+func WeightedSimilarityScore(left, right, weights []int64) int64 {
+	return weightedScore(left, weights, Frequencies(right))
+}
+
+// weightedScore is WeightedSimilarityScore against a prebuilt frequency map.
+func weightedScore(left, weights []int64, rightFreq map[int64]int64) int64 {
+	var totalScore int64
+	for i, leftNum := range left {
+		totalScore += weights[i] * leftNum * rightFreq[leftNum]
+	}
+	return totalScore
+}
+
+// SimilarityScoreSorted computes the same score as SimilarityScore with a merge-style
+// sweep over both lists in sorted order instead of a frequency map, which makes it
+// useful for cross-checking. Both slices are sorted in place.
+// Time Complexity: O(n log n + m log m) dominated by sorting
+// Space Complexity: O(1) beyond the input slices
+func SimilarityScoreSorted(left, right []int64) int64 {
+	SortColumns(left, right)
+	return sortedScore(left, right)
+}
+
+// sortedScore is the merge sweep behind SimilarityScoreSorted, for slices that are
+// already sorted.
+func sortedScore(left, right []int64) int64 {
+	var totalScore int64
+	i, j := 0, 0
+	for i < len(left) {
+		// Count the run of equal values on the left
+		value := left[i]
+		leftRun := int64(0)
+		for i < len(left) && left[i] == value {
+			leftRun++
+			i++
+		}
+
+		// Skip smaller right values, then count the matching run
+		for j < len(right) && right[j] < value {
+			j++
+		}
+		rightRun := int64(0)
+		for j < len(right) && right[j] == value {
+			rightRun++
+			j++
+		}
+
+		totalScore += value * leftRun * rightRun
+	}
+	return totalScore
+}
+
+// SortColumns sorts both columns ascending in place. SolveBoth, TopPairs and
+// SimilarityScoreSorted sort the same way, so callers wanting both an answer and
+// the sorted data can read the slices afterwards instead of sorting again.
+// Time Complexity: O(n log n + m log m), O(n + m) for already sorted input
+func SortColumns(left, right []int64) {
+	slices.Sort(left)
+	slices.Sort(right)
+}
+
+// TotalDistance pairs up both lists in sorted order and sums the absolute
+// differences. When one list is longer its largest values have no partner and are
+// left out; Columns from a parse are always balanced. It sorts copies and leaves
+// both slices untouched, so, like SimilarityScore, it is safe to call concurrently
+// on shared input. Callers that own the slices can avoid the copies with SolveBoth.
+// Time Complexity: O(n log n) dominated by sorting both lists
+// Space Complexity: O(n) for the sorted copies
+func TotalDistance(left, right []int64) int64 {
+	left, right = slices.Clone(left), slices.Clone(right)
+	SortColumns(left, right)
+	return sortedDistance(left, right)
+}
+
+// sortedDistance is the lockstep walk behind TotalDistance, for slices that are
+// already sorted.
+func sortedDistance(left, right []int64) int64 {
+	// Walk both sorted lists in lockstep, summing the distance of each pair
+	var total int64
+	for i := range min(len(left), len(right)) {
+		diff := left[i] - right[i]
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+	}
+	return total
+}
+
+// SolveBoth computes both answers from one sort: the distance from the lockstep
+// walk and the similarity from run lengths in the same sorted slices, so no
+// frequency map is built. Both slices are sorted in place, as by SortColumns.
+// Time Complexity: O(n log n) dominated by sorting both lists
+// Space Complexity: O(1) beyond the input slices
+func SolveBoth(left, right []int64) (distance, similarity int64) {
+	SortColumns(left, right)
+	return sortedDistance(left, right), sortedScore(left, right)
+}
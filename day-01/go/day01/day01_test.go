@@ -0,0 +1,556 @@
+package day01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"aoc-2024/internal/input"
+	"aoc-2024/internal/testutil"
+)
+
+// example is the puzzle's worked example: total distance 11, similarity score 31.
+const example = `3   4
+4   3
+2   5
+1   3
+3   9
+3   3
+`
+
+// writeInput writes content to a temporary file and returns its path.
+func writeInput(t testing.TB, content string) string {
+	t.Helper()
+
+	file, err := os.CreateTemp(t.TempDir(), "input-*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestExample(t *testing.T) {
+	cols, err := ParseColumns(writeInput(t, example), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseColumns: %v", err)
+	}
+	if got := TotalDistance(cols.Left, cols.Right); got != 11 {
+		t.Errorf("TotalDistance = %d, want 11", got)
+	}
+	if got := SimilarityScore(cols.Left, cols.Right); got != 31 {
+		t.Errorf("SimilarityScore = %d, want 31", got)
+	}
+}
+
+func TestSolveFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		distance   int64
+		similarity int64
+		skipped    int
+	}{
+		{name: "example", input: example, distance: 11, similarity: 31},
+		{name: "empty file", input: ""},
+		{name: "trailing blank lines", input: example + "\n\n\n", distance: 11, similarity: 31, skipped: 3},
+		{name: "three fields", input: "3 4\n4 3 7\n1 1\n", distance: 1, similarity: 1, skipped: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := SolveFile(writeInput(t, tt.input), ParseOptions{})
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			if res.TotalDistance != tt.distance || res.SimilarityScore != tt.similarity {
+				t.Errorf("answers = %d, %d, want %d, %d", res.TotalDistance, res.SimilarityScore, tt.distance, tt.similarity)
+			}
+			if res.LinesSkipped != tt.skipped {
+				t.Errorf("LinesSkipped = %d, want %d", res.LinesSkipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestTotalDistanceUnbalanced(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right []int64
+		want        int64
+	}{
+		{name: "shorter right", left: []int64{3, 1, 9}, right: []int64{2, 4}, want: 2},
+		{name: "shorter left", left: []int64{5}, right: []int64{7, 1}, want: 4},
+		{name: "empty right", left: []int64{1, 2}, right: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TotalDistance(tt.left, tt.right); got != tt.want {
+				t.Errorf("TotalDistance = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLargeValues(t *testing.T) {
+	// Both answers exceed math.MaxInt32, which would wrap in 32-bit arithmetic
+	input := fmt.Sprintf("%[1]d %[2]d\n%[1]d %[1]d\n%[1]d %[1]d\n", math.MaxInt32, -math.MaxInt32)
+	left, right := testutil.MustParseColumns(t, input)
+	if got, want := TotalDistance(left, right), int64(2*math.MaxInt32); got != want {
+		t.Errorf("TotalDistance = %d, want %d", got, want)
+	}
+	if got, want := SimilarityScore(left, right), int64(3*2*math.MaxInt32); got != want {
+		t.Errorf("SimilarityScore = %d, want %d", got, want)
+	}
+}
+
+func TestUnbalancedColumns(t *testing.T) {
+	// The second line's right value is not a number, so only its left one parses
+	path := writeInput(t, "3 4\n4 x\n1 2\n")
+	_, err := ParseColumns(path, ParseOptions{})
+	if err == nil {
+		t.Fatal("ParseColumns succeeded on unbalanced input")
+	}
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("error %v does not match ErrParse", err)
+	}
+	if !strings.Contains(err.Error(), "3 left values, 2 right values") {
+		t.Errorf("error %q does not give both counts", err)
+	}
+}
+
+func TestMissingTrailingNewline(t *testing.T) {
+	// Dropping the last pair, 3 3, would cut the similarity score from 31 to 16
+	input := strings.TrimSuffix(example, "\n")
+	for name, opts := range map[string]ParseOptions{
+		"buffered": {},
+		"mmap":     {Mmap: true},
+		"parallel": {Workers: 4},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := SolveFile(writeInput(t, input), opts)
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+				t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+			}
+			if res.LinesParsed != 6 {
+				t.Errorf("LinesParsed = %d, want 6", res.LinesParsed)
+			}
+		})
+	}
+}
+
+// endlessInput is a reader producing the same valid line forever.
+type endlessInput struct{}
+
+func (endlessInput) Read(p []byte) (int, error) {
+	const line = "12345   67890\n"
+	for i := range p {
+		p[i] = line[i%len(line)]
+	}
+	return len(p) - len(p)%len(line), nil
+}
+
+func TestSimilarityScoreContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := SimilarityScoreContext(ctx, endlessInput{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SimilarityScoreContext error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned %v after the deadline, want promptly", elapsed)
+	}
+}
+
+func TestNonNegative(t *testing.T) {
+	const input = "3 4\n-5 3\n2 5\n"
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			_, err := ReadColumns(strings.NewReader(input), ParseOptions{NonNegative: true, Strict: strict})
+			if err == nil {
+				t.Fatal("ReadColumns accepted a negative value")
+			}
+			if !strings.Contains(err.Error(), "line 2") || !strings.Contains(err.Error(), "negative") {
+				t.Errorf("error %q does not name the negative value's line", err)
+			}
+
+			// Off by default, the value is parsed like any other
+			cols, err := ReadColumns(strings.NewReader(input), ParseOptions{Strict: strict})
+			if err != nil {
+				t.Fatalf("ReadColumns without NonNegative: %v", err)
+			}
+			if cols.Left[1] != -5 {
+				t.Errorf("second left value = %d, want -5", cols.Left[1])
+			}
+		})
+	}
+}
+
+func TestLongLines(t *testing.T) {
+	// Padding between the values pushes the line past the initial 64KB buffer
+	long := "7" + strings.Repeat(" ", 100_000) + "7\n"
+	res := solveString(t, example+long, ParseOptions{})
+	if res.LinesParsed != 7 {
+		t.Errorf("LinesParsed = %d, want 7", res.LinesParsed)
+	}
+
+	// A line beyond the scanner's limit fails with a clear error
+	huge := "1 " + strings.Repeat("2", input.MaxLineSize) + "\n"
+	_, err := Solve(strings.NewReader(example+huge), ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "line 7 is longer than") {
+		t.Errorf("Solve error = %v, want line 7 reported as too long", err)
+	}
+}
+
+// randomColumns returns two columns of n values each drawn from [-spread, spread],
+// so a small spread gives many repeated values.
+func randomColumns(seed int64, n, spread int) (left, right []int64) {
+	rng := rand.New(rand.NewSource(seed))
+	left, right = make([]int64, n), make([]int64, n)
+	for i := range left {
+		left[i] = rng.Int63n(int64(2*spread+1)) - int64(spread)
+		right[i] = rng.Int63n(int64(2*spread+1)) - int64(spread)
+	}
+	return left, right
+}
+
+func FuzzSimilarityScoreSorted(f *testing.F) {
+	f.Add(int64(1), uint16(0), uint16(5))
+	f.Add(int64(2), uint16(100), uint16(3))
+	f.Add(int64(3), uint16(1000), uint16(50000))
+	f.Fuzz(func(t *testing.T, seed int64, n, spread uint16) {
+		left, right := randomColumns(seed, int(n), int(spread))
+		want := SimilarityScore(left, right)
+		if got := SimilarityScoreSorted(left, right); got != want {
+			t.Errorf("SimilarityScoreSorted = %d, SimilarityScore = %d", got, want)
+		}
+	})
+}
+
+func TestSimilarityOverlaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int64
+	}{
+		{name: "one left, three right", input: "3 3\n1 3\n2 3\n", want: 3 * 3},
+		// Each left 5 counts all three right 5s; 7 matches once
+		{name: "repeated on both sides", input: "5 5\n5 5\n7 5\n9 7\n", want: 5*3 + 5*3 + 7*1},
+		{name: "left only contributes 0", input: "4 1\n1 2\n", want: 1 * 1},
+		{name: "right only contributes nothing", input: "2 2\n6 8\n6 8\n", want: 2 * 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := testutil.MustParseColumns(t, tt.input)
+			if got := SimilarityScore(left, right); got != tt.want {
+				t.Errorf("SimilarityScore = %d, want %d", got, tt.want)
+			}
+			if got := SimilarityScoreSorted(slices.Clone(left), slices.Clone(right)); got != tt.want {
+				t.Errorf("SimilarityScoreSorted = %d, want %d", got, tt.want)
+			}
+			if _, got := SolveBoth(slices.Clone(left), slices.Clone(right)); got != tt.want {
+				t.Errorf("SolveBoth similarity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	left, right := randomColumns(7, 500, 100)
+	wantLeft, wantRight := slices.Clone(left), slices.Clone(right)
+	slices.Sort(wantLeft)
+	slices.Sort(wantRight)
+
+	SortColumns(left, right)
+	if !slices.IsSorted(left) || !slices.IsSorted(right) {
+		t.Fatal("SortColumns left a column out of ascending order")
+	}
+	// Sorting in place keeps every value
+	if !slices.Equal(left, wantLeft) || !slices.Equal(right, wantRight) {
+		t.Error("SortColumns changed the values of a column")
+	}
+
+	// SolveBoth leaves the columns sorted the same way for callers to reuse
+	left, right = randomColumns(8, 500, 100)
+	SolveBoth(left, right)
+	if !slices.IsSorted(left) || !slices.IsSorted(right) {
+		t.Error("SolveBoth did not leave both columns sorted")
+	}
+}
+
+func TestSimilarityNearMaxInt64(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocates columns of millions of values")
+	}
+	tests := []struct {
+		name   string
+		values []int64
+		// count is how often each value appears in both columns.
+		count int
+	}{
+		// 3·10^6 equal pairs of 10^6 score 9·10^18, just under math.MaxInt64
+		{name: "one value", values: []int64{1_000_000}, count: 3_000_000},
+		{name: "several values", values: []int64{2_000_000, 1_000_000, 1}, count: 1_500_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var left []int64
+			want := new(big.Int)
+			for _, v := range tt.values {
+				left = append(left, slices.Repeat([]int64{v}, tt.count)...)
+				// Each of the count left copies matches all count right copies
+				term := big.NewInt(v)
+				term.Mul(term, big.NewInt(int64(tt.count)))
+				term.Mul(term, big.NewInt(int64(tt.count)))
+				want.Add(want, term)
+			}
+			if !want.IsInt64() {
+				t.Fatalf("reference score %v does not fit in an int64", want)
+			}
+			right := slices.Clone(left)
+			slices.Reverse(right)
+
+			if got := SimilarityScore(left, right); got != want.Int64() {
+				t.Errorf("SimilarityScore = %d, want %v", got, want)
+			}
+			if got := SimilarityScoreSorted(slices.Clone(left), slices.Clone(right)); got != want.Int64() {
+				t.Errorf("SimilarityScoreSorted = %d, want %v", got, want)
+			}
+			if _, got := SolveBoth(slices.Clone(left), slices.Clone(right)); got != want.Int64() {
+				t.Errorf("SolveBoth similarity = %d, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSolveBoth(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		left, right := randomColumns(seed, 1000, 30)
+		wantDistance, wantSimilarity := TotalDistance(left, right), SimilarityScore(left, right)
+		distance, similarity := SolveBoth(left, right)
+		if distance != wantDistance || similarity != wantSimilarity {
+			t.Errorf("seed %d: SolveBoth = %d, %d, want %d, %d", seed, distance, similarity, wantDistance, wantSimilarity)
+		}
+	}
+	if distance, similarity := SolveBoth(nil, nil); distance != 0 || similarity != 0 {
+		t.Errorf("SolveBoth(nil, nil) = %d, %d, want 0, 0", distance, similarity)
+	}
+}
+
+// brokenAfter returns a reader yielding content and then failing with
+// "device unplugged".
+func brokenAfter(content string) io.Reader {
+	return io.MultiReader(strings.NewReader(content), iotest.ErrReader(errors.New("device unplugged")))
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		solve func() error
+	}{
+		{name: "ReadColumns", solve: func() error {
+			_, err := ReadColumns(brokenAfter(example), ParseOptions{})
+			return err
+		}},
+		{name: "Solve", solve: func() error {
+			_, err := Solve(brokenAfter(example), ParseOptions{})
+			return err
+		}},
+		{name: "Solver.Solve", solve: func() error {
+			_, err := NewSolver(ParseOptions{}).Solve(brokenAfter(example))
+			return err
+		}},
+		{name: "Pairs", solve: func() error {
+			pairs, errFn := Pairs(brokenAfter(example), ParseOptions{})
+			for range pairs {
+			}
+			return errFn()
+		}},
+		// Separate columns read two inputs in turn; a failure in either must surface
+		{name: "ReadSeparateColumns left", solve: func() error {
+			_, err := ReadSeparateColumns(brokenAfter(sortedLeft), strings.NewReader(sortedRight))
+			return err
+		}},
+		{name: "ReadSeparateColumns right", solve: func() error {
+			_, err := ReadSeparateColumns(strings.NewReader(sortedLeft), brokenAfter(sortedRight))
+			return err
+		}},
+		{name: "StreamTotalDistance", solve: func() error {
+			_, err := StreamTotalDistance(strings.NewReader(sortedLeft), brokenAfter(sortedRight))
+			return err
+		}},
+		{name: "SimilarityScoreContext", solve: func() error {
+			_, err := SimilarityScoreContext(context.Background(), brokenAfter(example))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.solve(); err == nil || !strings.Contains(err.Error(), "error reading input: device unplugged") {
+				t.Errorf("error = %v, want the read error", err)
+			}
+		})
+	}
+}
+
+func TestSortedDetection(t *testing.T) {
+	tests := []struct {
+		name                    string
+		input                   string
+		leftSorted, rightSorted bool
+	}{
+		{name: "both sorted", input: sortedInput(20_000, 0, 0), leftSorted: true, rightSorted: true},
+		{name: "equal values", input: "5 5\n5 5\n5 5\n", leftSorted: true, rightSorted: true},
+		{name: "left descends once", input: sortedInput(20_000, 12_345, 0), rightSorted: true},
+		{name: "right descends once", input: sortedInput(20_000, 0, 7), leftSorted: true},
+		{name: "example", input: example},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Chunks of a parallel parse must agree on order across their boundaries
+			for _, workers := range []int{1, 4} {
+				cols, err := ParseColumns(writeInput(t, tt.input), ParseOptions{Workers: workers})
+				if err != nil {
+					t.Fatalf("ParseColumns: %v", err)
+				}
+				if cols.LeftSorted() != tt.leftSorted || cols.RightSorted() != tt.rightSorted {
+					t.Errorf("%d workers: sorted = %v, %v, want %v, %v", workers, cols.LeftSorted(), cols.RightSorted(), tt.leftSorted, tt.rightSorted)
+				}
+			}
+		})
+	}
+
+	// Two sorted parts are unsorted together when the second starts lower
+	first := &Columns{Left: []int64{1, 5}, Right: []int64{1, 2}}
+	second := &Columns{Left: []int64{3, 9}, Right: []int64{2, 8}}
+	if merged := MergeColumns(first, second); merged.LeftSorted() || !merged.RightSorted() {
+		t.Errorf("merged sorted = %v, %v, want false, true", merged.LeftSorted(), merged.RightSorted())
+	}
+}
+
+// sortedInput returns n ascending pairs. A non-zero swapLeft or swapRight makes
+// that column fall once, by exchanging the values at that line and the next.
+func sortedInput(n, swapLeft, swapRight int) string {
+	left, right := make([]int, n), make([]int, n)
+	for i := range left {
+		left[i], right[i] = i, 2*i
+	}
+	if swapLeft > 0 {
+		left[swapLeft], left[swapLeft+1] = left[swapLeft+1], left[swapLeft]
+	}
+	if swapRight > 0 {
+		right[swapRight], right[swapRight+1] = right[swapRight+1], right[swapRight]
+	}
+	var b strings.Builder
+	for i := range left {
+		fmt.Fprintf(&b, "%d %d\n", left[i], right[i])
+	}
+	return b.String()
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	// Run with -race to check the calls share nothing mutable
+	left, right := randomColumns(9, 10_000, 500)
+	origLeft, origRight := slices.Clone(left), slices.Clone(right)
+	wantDistance, wantSimilarity := TotalDistance(left, right), SimilarityScore(left, right)
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := TotalDistance(left, right); got != wantDistance {
+				errs <- fmt.Sprintf("TotalDistance = %d, want %d", got, wantDistance)
+			}
+			if got := SimilarityScore(left, right); got != wantSimilarity {
+				errs <- fmt.Sprintf("SimilarityScore = %d, want %d", got, wantSimilarity)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if !slices.Equal(left, origLeft) || !slices.Equal(right, origRight) {
+		t.Error("concurrent calls modified the shared input")
+	}
+}
+
+func TestPartialResult(t *testing.T) {
+	res, err := Solve(brokenAfter(example+"oops\n"), ParseOptions{})
+	if err == nil {
+		t.Fatal("Solve succeeded on a failing reader")
+	}
+	if res == nil {
+		t.Fatal("Solve returned no partial result")
+	}
+	// Every line before the failure is counted and solved
+	if res.TotalDistance != 11 || res.SimilarityScore != 31 || res.LinesParsed != 6 || res.LinesSkipped != 1 {
+		t.Errorf("partial result = %+v, want the example's answers with 6 parsed and 1 skipped", res)
+	}
+
+	// A strict failure stops at the bad line, with the lines before it solved
+	res, err = Solve(strings.NewReader("3 4\n4 3\nx 1\n1 3\n"), ParseOptions{Strict: true})
+	if err == nil || res == nil {
+		t.Fatalf("strict Solve = %v, %v, want a partial result and an error", res, err)
+	}
+	if res.LinesParsed != 2 || res.TotalDistance != 0 || res.SimilarityScore != 7 {
+		t.Errorf("strict partial result = %+v, want 2 lines with answers 0 and 7", res)
+	}
+
+	// Nothing is solved when the input cannot be opened
+	if res, err := SolveFile(t.TempDir()+"/missing.txt", ParseOptions{}); err == nil || res != nil {
+		t.Errorf("SolveFile on a missing file = %v, %v, want no result and an error", res, err)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	// Weights stay with their lines, so which column is left matters
+	const input = "1 2 10\n2 3 1\n"
+	solve := func(swap bool) *Result {
+		t.Helper()
+		cols, err := ReadColumns(strings.NewReader(input), ParseOptions{Weighted: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if swap {
+			cols.Swap()
+		}
+		return NewResult(cols, Frequencies(cols.Right))
+	}
+	plain, swapped := solve(false), solve(true)
+	if plain.SimilarityScore != 2 || swapped.SimilarityScore != 20 {
+		t.Errorf("weighted scores = %d, %d, want 2 as read and 20 swapped", plain.SimilarityScore, swapped.SimilarityScore)
+	}
+	if plain.TotalDistance != swapped.TotalDistance {
+		t.Errorf("distance changed with the swap: %d, %d", plain.TotalDistance, swapped.TotalDistance)
+	}
+
+	// The unweighted score is symmetric
+	left, right := randomColumns(3, 300, 10)
+	if a, b := SimilarityScore(left, right), SimilarityScore(right, left); a != b {
+		t.Errorf("SimilarityScore = %d, swapped %d, want them equal", a, b)
+	}
+}
@@ -0,0 +1,85 @@
+package day01
+
+// maxDenseRange caps the slots of a dense frequency table, 64 MiB of counts.
+// Inputs whose right values span a wider range use the frequency map instead.
+const maxDenseRange = 1 << 23
+
+// denseTable counts right values in a slice indexed by their offset from the
+// smallest one, trading the hashing of a map lookup for an array index.
+type denseTable struct {
+	min    int64
+	counts []int64
+}
+
+// newDenseTable builds the table for right, or reports false when the values
+// span more than maxDenseRange.
+func newDenseTable(right []int64) (*denseTable, bool) {
+	if len(right) == 0 {
+		return &denseTable{}, true
+	}
+	lo, hi := right[0], right[0]
+	for _, v := range right[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	// Unsigned subtraction keeps the span exact even across the whole int64 range
+	if uint64(hi)-uint64(lo) >= maxDenseRange {
+		return nil, false
+	}
+
+	t := &denseTable{min: lo, counts: make([]int64, hi-lo+1)}
+	for _, v := range right {
+		t.counts[v-lo]++
+	}
+	return t, true
+}
+
+// count returns how often v occurs in the right list.
+func (t *denseTable) count(v int64) int64 {
+	i := uint64(v) - uint64(t.min)
+	if i >= uint64(len(t.counts)) {
+		return 0
+	}
+	return t.counts[i]
+}
+
+// SimilarityScoreDense computes the same score as SimilarityScore from a dense
+// slice of counts instead of a frequency map, which is faster when the right
+// values are clustered. It reports false, computing nothing, when they span too
+// wide a range for a slice.
+// Time Complexity: O(n + m + r) where r is the range of the right values
+// Space Complexity: O(r) for the counts
+func SimilarityScoreDense(left, right []int64) (int64, bool) {
+	table, ok := newDenseTable(right)
+	if !ok {
+		return 0, false
+	}
+	var totalScore int64
+	for _, leftNum := range left {
+		totalScore += leftNum * table.count(leftNum)
+	}
+	return totalScore, true
+}
+
+// NewDenseResult is NewResult with the similarity score, weighted when cols
+// carries Weights, computed from a dense table. It reports false, leaving cols
+// untouched, when the right values span too wide a range.
+func NewDenseResult(cols *Columns) (*Result, bool) {
+	table, ok := newDenseTable(cols.Right)
+	if !ok {
+		return nil, false
+	}
+
+	// Score before sorting, while Weights still line up with the left column
+	var similarity int64
+	for i, leftNum := range cols.Left {
+		contribution := leftNum * table.count(leftNum)
+		if cols.Weights != nil {
+			contribution *= cols.Weights[i]
+		}
+		similarity += contribution
+	}
+
+	SortColumns(cols.Left, cols.Right)
+	return cols.result(sortedDistance(cols.Left, cols.Right), similarity), true
+}
@@ -0,0 +1,78 @@
+package day01
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSimilarityScoreDense(t *testing.T) {
+	for _, spread := range []int{0, 3, 1000, 100_000} {
+		left, right := randomColumns(int64(spread), 2000, spread)
+		want := SimilarityScore(left, right)
+		got, ok := SimilarityScoreDense(left, right)
+		if !ok || got != want {
+			t.Errorf("spread %d: SimilarityScoreDense = %d, %v, want %d, true", spread, got, ok, want)
+		}
+	}
+
+	// Left values outside the right range count zero times
+	if got, ok := SimilarityScoreDense([]int64{-5, 1, 2, 99}, []int64{1, 1, 2}); !ok || got != 4 {
+		t.Errorf("SimilarityScoreDense out of range = %d, %v, want 4, true", got, ok)
+	}
+	if got, ok := SimilarityScoreDense([]int64{1}, nil); !ok || got != 0 {
+		t.Errorf("SimilarityScoreDense with no right values = %d, %v, want 0, true", got, ok)
+	}
+
+	// A range too wide for a slice falls back to the caller
+	if _, ok := SimilarityScoreDense([]int64{1}, []int64{math.MinInt64, math.MaxInt64}); ok {
+		t.Error("SimilarityScoreDense accepted a range spanning all of int64")
+	}
+	if _, ok := SimilarityScoreDense(nil, []int64{0, maxDenseRange}); ok {
+		t.Errorf("SimilarityScoreDense accepted a range of %d", maxDenseRange+1)
+	}
+}
+
+func TestNewDenseResult(t *testing.T) {
+	left, right := randomColumns(7, 500, 20)
+	weights := make([]int64, len(left))
+	for i := range weights {
+		weights[i] = int64(i%4 + 1)
+	}
+	dense := &Columns{Left: slices.Clone(left), Right: slices.Clone(right), Weights: slices.Clone(weights), Lines: len(left)}
+	mapped := &Columns{Left: left, Right: right, Weights: weights, Lines: len(left)}
+
+	got, ok := NewDenseResult(dense)
+	if !ok {
+		t.Fatal("NewDenseResult rejected a clustered input")
+	}
+	want := NewResult(mapped, Frequencies(mapped.Right))
+	if got.TotalDistance != want.TotalDistance || got.SimilarityScore != want.SimilarityScore {
+		t.Errorf("NewDenseResult = %d, %d, want %d, %d", got.TotalDistance, got.SimilarityScore, want.TotalDistance, want.SimilarityScore)
+	}
+
+	wide := &Columns{Left: []int64{2, 1}, Right: []int64{0, math.MaxInt64}}
+	if _, ok := NewDenseResult(wide); ok {
+		t.Fatal("NewDenseResult accepted a range spanning most of int64")
+	}
+	if !slices.Equal(wide.Left, []int64{2, 1}) {
+		t.Errorf("NewDenseResult sorted the columns it rejected: %v", wide.Left)
+	}
+}
+
+func BenchmarkSimilarityScoreDense(b *testing.B) {
+	// Clustered values: many repeats inside a narrow range
+	left, right := randomColumns(1, benchLines, 5000)
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SimilarityScore(left, right)
+		}
+	})
+	b.Run("dense", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SimilarityScoreDense(left, right)
+		}
+	})
+}
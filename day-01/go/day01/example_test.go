@@ -0,0 +1,34 @@
+package day01_test
+
+import (
+	"fmt"
+	"strings"
+
+	"aoc-2024/day-01/go/day01"
+)
+
+// sample is the puzzle's worked example.
+const sample = "3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n"
+
+func ExampleSimilarityScore() {
+	cols, err := day01.ReadColumns(strings.NewReader(sample), day01.ParseOptions{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(day01.SimilarityScore(cols.Left, cols.Right))
+	fmt.Println(day01.TotalDistance(cols.Left, cols.Right))
+	// Output:
+	// 31
+	// 11
+}
+
+func ExampleSolve() {
+	res, err := day01.Solve(strings.NewReader(sample), day01.ParseOptions{Strict: true})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(res.TotalDistance, res.SimilarityScore, res.LinesParsed)
+	// Output: 11 31 6
+}
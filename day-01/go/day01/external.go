@@ -0,0 +1,227 @@
+package day01
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// estimatedBytesPerLine approximates the memory the in-memory solve needs per
+// input line: both columns plus a frequency map entry with its share of bucket
+// overhead.
+const estimatedBytesPerLine = 64
+
+// EstimateMemory returns the approximate bytes the in-memory solve needs for an
+// input of size bytes, from the typical line length. It returns -1 when size is
+// unknown.
+func EstimateMemory(size int64) int64 {
+	if size < 0 {
+		return -1
+	}
+	return int64(capacityHint(size)) * estimatedBytesPerLine
+}
+
+// minRunSize is the smallest run ExternalTotalDistance spills, which bounds the
+// number of run files open at once during the merge.
+const minRunSize = 1 << 16
+
+// ExternalTotalDistance computes the total distance of the input in filename
+// while holding at most runSize values of each column in memory. Each column is
+// cut into sorted runs spilled to temporary files, which are then merged in
+// lockstep, so only the distance is available; the similarity score needs every
+// value at once. Lines are read as Pairs reads them, and the temporary files are
+// removed before returning. A runSize below 65536 values is raised to it.
+// Time Complexity: O(n log n) for sorting the runs and merging them
+// Space Complexity: O(runSize) in memory, O(n) on disk
+func ExternalTotalDistance(filename string, opts ParseOptions, runSize int) (int64, error) {
+	runSize = max(runSize, minRunSize)
+
+	var leftRuns, rightRuns runFiles
+	defer func() {
+		leftRuns.remove()
+		rightRuns.remove()
+	}()
+
+	left := make([]int64, 0, runSize)
+	right := make([]int64, 0, runSize)
+	pairs, pairsErr := PairsFile(filename, opts)
+	var spillErr error
+	for l, r := range pairs {
+		left = append(left, l)
+		right = append(right, r)
+		if len(left) == runSize {
+			if spillErr = spillRuns(&leftRuns, &rightRuns, left, right); spillErr != nil {
+				break
+			}
+			left, right = left[:0], right[:0]
+		}
+	}
+	if err := errors.Join(spillErr, pairsErr()); err != nil {
+		return 0, err
+	}
+
+	// Everything fit in one run: no need to touch the disk
+	if len(leftRuns) == 0 {
+		SortColumns(left, right)
+		return sortedDistance(left, right), nil
+	}
+	if len(left) > 0 {
+		if err := spillRuns(&leftRuns, &rightRuns, left, right); err != nil {
+			return 0, err
+		}
+	}
+	return mergedDistance(leftRuns, rightRuns)
+}
+
+// spillRuns sorts one run of each column and writes it to a new temporary file.
+func spillRuns(leftRuns, rightRuns *runFiles, left, right []int64) error {
+	SortColumns(left, right)
+	if err := leftRuns.write(left); err != nil {
+		return err
+	}
+	return rightRuns.write(right)
+}
+
+// runFiles holds the temporary files of one column's sorted runs.
+type runFiles []*os.File
+
+// write stores values, already sorted, as a new run of little-endian int64s.
+func (f *runFiles) write(values []int64) error {
+	file, err := os.CreateTemp("", "day01-run-*")
+	if err != nil {
+		return fmt.Errorf("error creating sort run: %v", err)
+	}
+	*f = append(*f, file)
+
+	w := bufio.NewWriter(file)
+	var buf [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		w.Write(buf[:])
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("error writing sort run: %v", err)
+	}
+	return nil
+}
+
+// remove closes and deletes every run file.
+func (f runFiles) remove() {
+	for _, file := range f {
+		file.Close()
+		os.Remove(file.Name())
+	}
+}
+
+// mergedDistance merges the runs of each column and sums the distance of the
+// pairs in sorted order.
+func mergedDistance(leftRuns, rightRuns runFiles) (int64, error) {
+	leftValues, err := newRunMerger(leftRuns)
+	if err != nil {
+		return 0, err
+	}
+	rightValues, err := newRunMerger(rightRuns)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for {
+		l, lok, err := leftValues.next()
+		if err != nil {
+			return 0, err
+		}
+		r, rok, err := rightValues.next()
+		if err != nil {
+			return 0, err
+		}
+		if !lok || !rok {
+			if lok != rok {
+				// Pairs are read whole, so the runs always hold as many values
+				return 0, errors.New("sort runs of the two columns differ in length")
+			}
+			return total, nil
+		}
+		diff := l - r
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+	}
+}
+
+// runReader reads one sorted run back, holding its smallest unread value.
+type runReader struct {
+	r    *bufio.Reader
+	head int64
+}
+
+// advance loads the next value into head, reporting false at the end of the run.
+func (rr *runReader) advance() (bool, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(rr.r, buf[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading sort run: %v", err)
+	}
+	rr.head = int64(binary.LittleEndian.Uint64(buf[:]))
+	return true, nil
+}
+
+// runMerger yields the values of several sorted runs in ascending order,
+// keeping the runs in a min-heap by their head value.
+type runMerger []*runReader
+
+func newRunMerger(runs runFiles) (*runMerger, error) {
+	m := make(runMerger, 0, len(runs))
+	for _, file := range runs {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("error reading sort run: %v", err)
+		}
+		rr := &runReader{r: bufio.NewReader(file)}
+		ok, err := rr.advance()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			m = append(m, rr)
+		}
+	}
+	heap.Init(&m)
+	return &m, nil
+}
+
+// next returns the smallest remaining value, or false once every run is drained.
+func (m *runMerger) next() (int64, bool, error) {
+	if len(*m) == 0 {
+		return 0, false, nil
+	}
+	top := (*m)[0]
+	value := top.head
+	ok, err := top.advance()
+	if err != nil {
+		return 0, false, err
+	}
+	if ok {
+		heap.Fix(m, 0)
+	} else {
+		heap.Pop(m)
+	}
+	return value, true, nil
+}
+
+func (m runMerger) Len() int           { return len(m) }
+func (m runMerger) Less(i, j int) bool { return m[i].head < m[j].head }
+func (m runMerger) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m *runMerger) Push(x any)        { *m = append(*m, x.(*runReader)) }
+func (m *runMerger) Pop() any {
+	old := *m
+	last := old[len(old)-1]
+	*m = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,42 @@
+package day01
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestExternalTotalDistance(t *testing.T) {
+	// More than three runs of the minimum size, so the merge path is taken
+	var buf bytes.Buffer
+	if err := Generate(&buf, 3*minRunSize+100, 4); err != nil {
+		t.Fatal(err)
+	}
+	path := writeInput(t, buf.String())
+	want, err := SolveFile(path, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	for _, runSize := range []int{0, minRunSize, 10 * minRunSize} {
+		got, err := ExternalTotalDistance(path, ParseOptions{}, runSize)
+		if err != nil {
+			t.Fatalf("run size %d: %v", runSize, err)
+		}
+		if got != want.TotalDistance {
+			t.Errorf("run size %d: distance = %d, want %d", runSize, got, want.TotalDistance)
+		}
+	}
+	if left, _ := filepath.Glob(filepath.Join(tmp, "*")); len(left) != 0 {
+		t.Errorf("temporary run files left behind: %v", left)
+	}
+
+	if _, err := ExternalTotalDistance(filepath.Join(tmp, "missing.txt"), ParseOptions{}, 0); err == nil {
+		t.Error("ExternalTotalDistance succeeded on a missing file")
+	}
+	if EstimateMemory(-1) != -1 || EstimateMemory(int64(buf.Len())) <= EstimateMemory(100) {
+		t.Error("EstimateMemory does not grow with the input size")
+	}
+}
@@ -0,0 +1,50 @@
+//go:build unix
+
+package day01
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// writeFIFO creates a named pipe and writes content to it from another goroutine,
+// returning the pipe's path. Each pipe can be read once.
+func writeFIFO(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "input.fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skipf("cannot create a named pipe: %v", err)
+	}
+	go func() {
+		// Opening for writing blocks until the reader opens the pipe
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		file.WriteString(content)
+	}()
+	return path
+}
+
+func TestNamedPipe(t *testing.T) {
+	for name, opts := range map[string]ParseOptions{
+		"buffered": {},
+		"mmap":     {Mmap: true},
+		"parallel": {Workers: 4},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// A pipe cannot be mapped or read in chunks, so every path reads it once in order
+			res, err := SolveFile(writeFIFO(t, example), opts)
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+				t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+			}
+		})
+	}
+}
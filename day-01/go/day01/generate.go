@@ -0,0 +1,54 @@
+package day01
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+const (
+	// generatedMin and generatedMax bound the five-digit values of generated
+	// inputs, matching the shape of real puzzle input.
+	generatedMin = 10000
+	generatedMax = 99999
+)
+
+// Generate writes n lines of two random values in the puzzle input format.
+// The same seed always produces byte-identical output.
+// Time Complexity: O(n)
+// Space Complexity: O(1) beyond the write buffer
+func Generate(w io.Writer, n int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	bw := bufio.NewWriter(w)
+
+	line := make([]byte, 0, 16)
+	for i := 0; i < n; i++ {
+		left := generatedMin + rng.Int63n(generatedMax-generatedMin+1)
+		right := generatedMin + rng.Int63n(generatedMax-generatedMin+1)
+
+		line = strconv.AppendInt(line[:0], left, 10)
+		line = append(line, "   "...)
+		line = strconv.AppendInt(line, right, 10)
+		line = append(line, '\n')
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Shuffle reorders the parsed lines of c with a seeded RNG, keeping each left
+// value with its right value and weight. Neither answer depends on line order, so
+// solving a shuffled copy is a check for an order-dependent bug.
+// Time Complexity: O(n)
+func (c *Columns) Shuffle(seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(c.Left), func(i, j int) {
+		c.Left[i], c.Left[j] = c.Left[j], c.Left[i]
+		c.Right[i], c.Right[j] = c.Right[j], c.Right[i]
+		if c.Weights != nil {
+			c.Weights[i], c.Weights[j] = c.Weights[j], c.Weights[i]
+		}
+	})
+}
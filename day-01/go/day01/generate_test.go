@@ -0,0 +1,96 @@
+package day01
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	generate := func(seed int64) string {
+		var buf bytes.Buffer
+		if err := Generate(&buf, 500, seed); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	first := generate(42)
+	if again := generate(42); again != first {
+		t.Error("two runs with the same seed differ")
+	}
+	if other := generate(43); other == first {
+		t.Error("different seeds produced the same input")
+	}
+
+	cols, err := ReadColumns(strings.NewReader(first), ParseOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("generated input does not parse: %v", err)
+	}
+	if len(cols.Left) != 500 {
+		t.Errorf("generated %d lines, want 500", len(cols.Left))
+	}
+	for i := range cols.Left {
+		for _, v := range []int64{cols.Left[i], cols.Right[i]} {
+			if v < generatedMin || v > generatedMax {
+				t.Fatalf("line %d holds %d, outside [%d, %d]", i+1, v, generatedMin, generatedMax)
+			}
+		}
+	}
+}
+
+// shuffleLines returns the lines of input in a seeded random order.
+func shuffleLines(input string, seed int64) string {
+	lines := strings.SplitAfter(input, "\n")
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(lines), func(i, j int) { lines[i], lines[j] = lines[j], lines[i] })
+	return strings.Join(lines, "")
+}
+
+func TestOrderIndependence(t *testing.T) {
+	var generated bytes.Buffer
+	if err := Generate(&generated, 300, 5); err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string]string{
+		"example": example,
+		// Few distinct values, so similarity depends on many repeats
+		"repeats":   strings.Repeat(example, 20),
+		"generated": generated.String(),
+	}
+	for name, input := range inputs {
+		want := solveString(t, input, ParseOptions{})
+		for seed := int64(1); seed <= 20; seed++ {
+			got := solveString(t, shuffleLines(input, seed), ParseOptions{})
+			if got.TotalDistance != want.TotalDistance || got.SimilarityScore != want.SimilarityScore {
+				t.Errorf("%s shuffled with seed %d: answers = %d, %d, want %d, %d",
+					name, seed, got.TotalDistance, got.SimilarityScore, want.TotalDistance, want.SimilarityScore)
+			}
+		}
+	}
+}
+
+func TestColumnsShuffle(t *testing.T) {
+	// Each weight encodes its pair, so a pair split up by the shuffle shows
+	var b strings.Builder
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&b, "%d %d %d\n", i, 100+i, 1000*i+100+i)
+	}
+	cols, err := ReadColumns(strings.NewReader(b.String()), ParseOptions{Weighted: true})
+	if err != nil {
+		t.Fatalf("ReadColumns: %v", err)
+	}
+	cols.Shuffle(3)
+	moved := false
+	for i := range cols.Left {
+		if cols.Weights[i] != 1000*cols.Left[i]+cols.Right[i] {
+			t.Fatalf("line %d holds %d %d %d, a broken pair", i+1, cols.Left[i], cols.Right[i], cols.Weights[i])
+		}
+		moved = moved || cols.Left[i] != int64(i+1)
+	}
+	if !moved {
+		t.Error("Shuffle left every line in place")
+	}
+}
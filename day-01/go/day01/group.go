@@ -0,0 +1,33 @@
+package day01
+
+// GroupSums treats the columns as key/value pairs and sums the values of each key,
+// for puzzle variants that aggregate by the left column.
+// Time Complexity: O(n)
+// Space Complexity: O(k) for k distinct keys
+func GroupSums(keys, values []int64) map[int64]int64 {
+	sums := make(map[int64]int64)
+	for i, key := range keys {
+		sums[key] += values[i]
+	}
+	return sums
+}
+
+// DedupLeft returns the distinct values of left in first-occurrence order, for
+// the puzzle variant where each left ID counts once. Scoring the result with
+// ScoreWithFrequencies sums value × frequency per unique value rather than per
+// occurrence, so it differs from SimilarityScore whenever left repeats a value
+// that occurs in the right list.
+// Time Complexity: O(n)
+// Space Complexity: O(k) for k distinct values
+func DedupLeft(left []int64) []int64 {
+	seen := make(map[int64]struct{}, len(left))
+	unique := make([]int64, 0, len(left))
+	for _, v := range left {
+		if _, dup := seen[v]; dup {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+	return unique
+}
@@ -0,0 +1,42 @@
+package day01
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGroupSums(t *testing.T) {
+	keys := []int64{3, 1, 3, -2, 1, 3}
+	values := []int64{10, 5, -4, 7, 0, 1}
+	want := map[int64]int64{3: 7, 1: 5, -2: 7}
+	if got := GroupSums(keys, values); !maps.Equal(got, want) {
+		t.Errorf("GroupSums = %v, want %v", got, want)
+	}
+	if got := GroupSums(nil, nil); len(got) != 0 {
+		t.Errorf("GroupSums of no pairs = %v, want an empty map", got)
+	}
+}
+
+func TestDedupLeft(t *testing.T) {
+	left := []int64{3, 4, 2, 1, 3, 3}
+	right := []int64{4, 3, 5, 3, 9, 3}
+	unique := DedupLeft(left)
+	if want := []int64{3, 4, 2, 1}; !slices.Equal(unique, want) {
+		t.Errorf("DedupLeft = %v, want %v in first-occurrence order", unique, want)
+	}
+	if !slices.Equal(left, []int64{3, 4, 2, 1, 3, 3}) {
+		t.Errorf("DedupLeft modified its input: %v", left)
+	}
+
+	// The three 3s count once, so 3×3 + 4×1 instead of the example's 31
+	freq := Frequencies(right)
+	if got, full := ScoreWithFrequencies(unique, freq), SimilarityScore(left, right); got != 13 || full != 31 {
+		t.Errorf("deduped score = %d, full score = %d, want 13 and 31", got, full)
+	}
+	// Without repeats the two agree
+	distinct := []int64{5, 9, 1}
+	if a, b := ScoreWithFrequencies(DedupLeft(distinct), freq), SimilarityScore(distinct, right); a != b {
+		t.Errorf("deduped score = %d, full score = %d for distinct values, want them equal", a, b)
+	}
+}
@@ -0,0 +1,270 @@
+package day01
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"aoc-2024/internal/input"
+)
+
+// ErrFileNotFound is returned, wrapped, when the puzzle input does not exist.
+var ErrFileNotFound = errors.New("input file not found")
+
+// OpenInput opens a puzzle input file, reporting the resolved path on failure and
+// wrapping ErrFileNotFound when it does not exist. A directory is rejected up front
+// rather than surfacing as an opaque read error.
+func OpenInput(filename string) (*os.File, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, absPath(filename))
+		}
+		wrapped := fmt.Errorf("error opening file %s: %v", absPath(filename), err)
+		if isTransientErrno(err) {
+			return nil, &transientError{wrapped}
+		}
+		return nil, wrapped
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error opening file %s: %v", absPath(filename), err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("expected a file, got a directory: %s", absPath(filename))
+	}
+	return file, nil
+}
+
+// absPath resolves filename for error messages, falling back to it unchanged.
+func absPath(filename string) string {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return filename
+	}
+	return path
+}
+
+// source is an opened puzzle input: a file, stdin, or a decompressing reader.
+type source struct {
+	io.Reader
+	// file is set when the input is an uncompressed file that can be read in chunks.
+	file    *os.File
+	closers []io.Closer
+}
+
+// openSource opens filename, or stdin when filename is "-", or streams the
+// response body when filename is an http:// or https:// URL. Input named *.gz,
+// or any input when opts.Gzip is set, is decompressed.
+func openSource(filename string, opts ParseOptions) (*source, error) {
+	src := &source{}
+	var in io.Reader = os.Stdin
+	switch {
+	case isURL(filename):
+		var body io.ReadCloser
+		err := retry(opts.Retries, func() (err error) {
+			body, err = openBody(filename, opts.Timeout)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		src.closers = append(src.closers, body)
+		in = body
+	case filename != "-":
+		// Open file with error handling
+		var file *os.File
+		err := retry(opts.Retries, func() (err error) {
+			file, err = OpenInput(filename)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		src.closers = append(src.closers, file)
+		src.file = file
+		in = file
+	}
+
+	if opts.Gzip || strings.HasSuffix(filename, ".gz") {
+		// Decompressed input cannot be read in chunks
+		src.file = nil
+		zr, err := gzip.NewReader(in)
+		if err != nil {
+			src.Close()
+			return nil, fmt.Errorf("error reading gzip input: %v", err)
+		}
+		src.closers = append(src.closers, zr)
+		in = zr
+	}
+
+	src.Reader = decodeUTF16(src, in)
+	return src, nil
+}
+
+// decodeUTF16 returns in, transcoded to UTF-8 when it starts with a UTF-16 byte
+// order mark. A file is sniffed with ReadAt so it can still be read in chunks
+// when it is not UTF-16.
+func decodeUTF16(src *source, in io.Reader) io.Reader {
+	var prefix []byte
+	if src.file != nil {
+		buf := make([]byte, 2)
+		n, _ := src.file.ReadAt(buf, 0)
+		prefix = buf[:n]
+	} else {
+		br := bufio.NewReader(in)
+		prefix, _ = br.Peek(2)
+		in = br
+	}
+
+	isUTF16, bigEndian := input.SniffUTF16(prefix)
+	if !isUTF16 {
+		return in
+	}
+	// Transcoded input cannot be read in chunks
+	src.file = nil
+	return input.NewUTF16Reader(in, bigEndian)
+}
+
+// retryBackoff is the pause before the first retry of a failed open; each
+// further retry waits twice as long as the one before.
+const retryBackoff = 100 * time.Millisecond
+
+// retry calls open until it succeeds, fails with an error that is not transient,
+// or has been retried the given number of times, and returns its last error.
+func retry(retries int, open func() error) error {
+	err := open()
+	for attempt := 0; err != nil && attempt < retries && isTransient(err); attempt++ {
+		time.Sleep(retryBackoff << attempt)
+		err = open()
+	}
+	return err
+}
+
+// transientError marks an error opening an input that may clear up on its own,
+// so retry tries again. Its message is the underlying error's.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
+
+// isTransient reports whether err is worth retrying. Missing or unreadable
+// files, directories and client error statuses fail the same way every time.
+func isTransient(err error) bool {
+	var transient *transientError
+	return errors.As(err, &transient)
+}
+
+// isTransientErrno reports whether a failed file open was interrupted or ran
+// out of resources, rather than being refused.
+func isTransientErrno(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EINTR, syscall.EAGAIN, syscall.EIO, syscall.EMFILE, syscall.ENFILE, syscall.ETIMEDOUT} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientFetch reports whether a failed request or first read of a URL
+// input hit the network, a timeout or a connection closed mid-response.
+func isTransientFetch(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isURL reports whether filename names an http:// or https:// input.
+func isURL(filename string) bool {
+	return strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://")
+}
+
+// openBody fetches a URL input. Tests wrap it to check the body is closed.
+var openBody = openURL
+
+// openURL requests url and returns the response body to stream the input from.
+// timeout bounds the whole fetch, body included; zero means no limit. A 404 wraps
+// ErrFileNotFound like a missing file, while network errors, timeouts and 5xx or
+// 429 statuses are transient. Each fetch uses its own transport, whose connection
+// is closed with the body rather than left idle in a shared pool.
+func openURL(url string, timeout time.Duration) (io.ReadCloser, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	client := &http.Client{Transport: transport, Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		transport.CloseIdleConnections()
+		wrapped := fmt.Errorf("error fetching input: %v", err)
+		if isTransientFetch(err) {
+			return nil, &transientError{wrapped}
+		}
+		return nil, wrapped
+	}
+	body := &urlBody{ReadCloser: resp.Body, transport: transport}
+	if resp.StatusCode != http.StatusOK {
+		body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, url)
+		}
+		err := fmt.Errorf("error fetching input %s: %s", url, resp.Status)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &transientError{err}
+		}
+		return nil, err
+	}
+
+	// Read the first bytes here, so a connection dropped before any data
+	// arrives is retried like a failed request
+	body.r = bufio.NewReader(resp.Body)
+	if _, err := body.r.Peek(1); err != nil && err != io.EOF {
+		body.Close()
+		wrapped := fmt.Errorf("error reading input %s: %v", url, err)
+		if isTransientFetch(err) {
+			return nil, &transientError{wrapped}
+		}
+		return nil, wrapped
+	}
+	return body, nil
+}
+
+// urlBody is a response body that shuts down its transport's connections when
+// closed, so no keep-alive goroutines or sockets outlive the input.
+type urlBody struct {
+	io.ReadCloser
+	transport *http.Transport
+	// r buffers the bytes openURL read ahead of the caller.
+	r *bufio.Reader
+}
+
+func (b *urlBody) Read(p []byte) (int, error) { return b.r.Read(p) }
+
+func (b *urlBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.transport.CloseIdleConnections()
+	return err
+}
+
+// Close releases everything opened for the input; stdin is left open.
+func (s *source) Close() error {
+	var first error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,337 @@
+package day01
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestGzipInput(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(example)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	named := filepath.Join(dir, "input.txt.gz")
+	flagged := filepath.Join(dir, "input.bin")
+	for _, path := range []string{named, flagged} {
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		path string
+		opts ParseOptions
+	}{
+		{name: "gz suffix", path: named},
+		{name: "gzip option", path: flagged, opts: ParseOptions{Gzip: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := SolveFile(tt.path, tt.opts)
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			if res.SimilarityScore != 31 || res.TotalDistance != 11 {
+				t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+			}
+		})
+	}
+}
+
+func TestDirectoryInput(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ParseColumns(dir, ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "expected a file, got a directory: "+dir) {
+		t.Errorf("ParseColumns error = %v, want the directory named as not a file", err)
+	}
+}
+
+func TestBOM(t *testing.T) {
+	for name, opts := range map[string]ParseOptions{
+		"buffered": {},
+		"mmap":     {Mmap: true},
+		"parallel": {Workers: 4},
+		"strict":   {Strict: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := SolveFile(writeInput(t, "\xEF\xBB\xBF"+example), opts)
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			// Without the BOM stripped the first pair, 3 4, is skipped
+			if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+				t.Errorf("answers = %d, %d, want 11, 31 as without the BOM", res.TotalDistance, res.SimilarityScore)
+			}
+			if res.LinesParsed != 6 || res.LinesSkipped != 0 {
+				t.Errorf("LinesParsed = %d, LinesSkipped = %d, want 6 and 0", res.LinesParsed, res.LinesSkipped)
+			}
+		})
+	}
+}
+
+func TestProgressCounts(t *testing.T) {
+	progress := &Progress{}
+	if _, err := ParseColumns(writeInput(t, example), ParseOptions{Progress: progress}); err != nil {
+		t.Fatalf("ParseColumns: %v", err)
+	}
+	if progress.Lines() != 6 || progress.Bytes() != int64(len(example)) {
+		t.Errorf("progress = %d lines, %d bytes, want 6 lines, %d bytes", progress.Lines(), progress.Bytes(), len(example))
+	}
+}
+
+func TestURLInput(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/input", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, example)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	res, err := SolveFile(srv.URL+"/input", ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+		t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+	}
+
+	if _, err := SolveFile(srv.URL+"/missing", ParseOptions{}); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("SolveFile on a 404 = %v, want ErrFileNotFound", err)
+	}
+	start := time.Now()
+	if _, err := SolveFile(srv.URL+"/slow", ParseOptions{Timeout: 50 * time.Millisecond}); err == nil {
+		t.Error("SolveFile on a stalled server succeeded, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("timed out fetch took %v, want about the 50ms timeout", elapsed)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	// The server fails every other request, starting with the first
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			requests.Add(1)
+			http.NotFound(w, r)
+			return
+		}
+		if requests.Add(1)%2 == 1 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, example)
+	}))
+	defer srv.Close()
+
+	if _, err := SolveFile(srv.URL, ParseOptions{}); err == nil {
+		t.Fatal("SolveFile succeeded on a failing first request without retries")
+	}
+	requests.Store(0)
+	res, err := SolveFile(srv.URL, ParseOptions{Retries: 2})
+	if err != nil {
+		t.Fatalf("SolveFile with retries: %v", err)
+	}
+	if res.SimilarityScore != 31 {
+		t.Errorf("similarity = %d, want 31", res.SimilarityScore)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want the failure and one retry", n)
+	}
+
+	// Not found is final, so it is not retried
+	requests.Store(0)
+	if _, err := SolveFile(srv.URL+"/missing", ParseOptions{Retries: 2}); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("SolveFile on a 404 = %v, want ErrFileNotFound", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server saw %d requests for a 404, want 1", n)
+	}
+}
+
+func TestRetriesPermanent(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "go away", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	// A client error fails the same way every time, so it is not retried
+	if _, err := SolveFile(srv.URL, ParseOptions{Retries: 2}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("SolveFile on a 403 = %v, want the status", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server saw %d requests for a 403, want 1", n)
+	}
+
+	// Neither is a directory, which would otherwise sleep through every backoff
+	start := time.Now()
+	if _, err := SolveFile(t.TempDir(), ParseOptions{Retries: 3}); err == nil || !strings.Contains(err.Error(), "directory") {
+		t.Errorf("SolveFile on a directory = %v, want the directory rejected", err)
+	}
+	if elapsed := time.Since(start); elapsed >= retryBackoff {
+		t.Errorf("SolveFile on a directory took %v, want no retries", elapsed)
+	}
+
+	// Or an unreadable file, which root can read anyway
+	if os.Geteuid() == 0 {
+		return
+	}
+	path := writeInput(t, example)
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	start = time.Now()
+	if _, err := SolveFile(path, ParseOptions{Retries: 3}); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("SolveFile on an unreadable file = %v, want permission denied", err)
+	}
+	if elapsed := time.Since(start); elapsed >= retryBackoff {
+		t.Errorf("SolveFile on an unreadable file took %v, want no retries", elapsed)
+	}
+}
+
+func TestRetriesDroppedBody(t *testing.T) {
+	// The first response promises a body and drops the connection before it
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		io.WriteString(w, example)
+	}))
+	defer srv.Close()
+
+	res, err := SolveFile(srv.URL, ParseOptions{Retries: 1})
+	if err != nil {
+		t.Fatalf("SolveFile after a dropped body: %v", err)
+	}
+	if res.SimilarityScore != 31 {
+		t.Errorf("similarity = %d, want 31", res.SimilarityScore)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want the dropped one and one retry", n)
+	}
+}
+
+// encodeUTF16 returns s as UTF-16 of the given byte order, led by a byte order mark.
+func encodeUTF16(s string, bigEndian bool) []byte {
+	order := binary.AppendByteOrder(binary.LittleEndian)
+	if bigEndian {
+		order = binary.BigEndian
+	}
+	var out []byte
+	for _, unit := range utf16.Encode([]rune("\uFEFF" + s)) {
+		out = order.AppendUint16(out, unit)
+	}
+	return out
+}
+
+func TestUTF16(t *testing.T) {
+	for _, bigEndian := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "input.txt")
+		if err := os.WriteFile(path, encodeUTF16(example, bigEndian), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		res, err := SolveFile(path, ParseOptions{Strict: true})
+		if err != nil {
+			t.Fatalf("big-endian %v: %v", bigEndian, err)
+		}
+		if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+			t.Errorf("big-endian %v: answers = %d, %d, want 11, 31", bigEndian, res.TotalDistance, res.SimilarityScore)
+		}
+	}
+
+	// A reader handed straight to the parser is not transcoded, so it fails clearly
+	_, err := ReadColumns(bytes.NewReader(encodeUTF16(example, false)), ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "UTF-16") {
+		t.Errorf("ReadColumns on UTF-16LE = %v, want an error naming UTF-16", err)
+	}
+}
+
+// countingBody counts how often a response body is closed.
+type countingBody struct {
+	io.ReadCloser
+	closes *atomic.Int32
+}
+
+func (b countingBody) Close() error {
+	b.closes.Add(1)
+	return b.ReadCloser.Close()
+}
+
+func TestURLBodyClosed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, example)
+	}))
+	defer srv.Close()
+
+	var closes atomic.Int32
+	t.Cleanup(func() { openBody = openURL })
+	openBody = func(url string, timeout time.Duration) (io.ReadCloser, error) {
+		body, err := openURL(url, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return countingBody{body, &closes}, nil
+	}
+
+	if _, err := SolveFile(srv.URL, ParseOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := closes.Swap(0); n != 1 {
+		t.Errorf("body closed %d times after a solve, want once", n)
+	}
+
+	pairs, pairsErr := PairsFile(srv.URL, ParseOptions{})
+	for range pairs {
+		break
+	}
+	if err := pairsErr(); err != nil {
+		t.Fatal(err)
+	}
+	if n := closes.Swap(0); n != 1 {
+		t.Errorf("body closed %d times after an early break, want once", n)
+	}
+
+	// A strict failure stops reading partway through the body
+	_, err := SolveFile(srv.URL, ParseOptions{Strict: true, FieldIndices: []int{0, 5}})
+	if err == nil {
+		t.Fatal("SolveFile succeeded with out-of-range field indices")
+	}
+	if n := closes.Load(); n != 1 {
+		t.Errorf("body closed %d times after a strict failure, want once", n)
+	}
+}
@@ -0,0 +1,103 @@
+package day01
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+
+	"aoc-2024/internal/input"
+)
+
+// Pairs returns an iterator over the (left, right) pairs of r, parsed line by line
+// as ReadColumns would but without collecting them, and a function returning the
+// error that ended the iteration, if any. Call it once the loop is done:
+//
+//	pairs, pairsErr := day01.Pairs(r, day01.ParseOptions{})
+//	for left, right := range pairs {
+//		...
+//	}
+//	if err := pairsErr(); err != nil {
+//		return err
+//	}
+//
+// Malformed lines are skipped unless opts.Strict is set, and a line holding only
+// one valid value yields nothing. The options that shape collected columns,
+// Workers, Mmap, Pad, SkipSamples and Progress, have no effect.
+func Pairs(r io.Reader, opts ParseOptions) (iter.Seq2[int64, int64], func() error) {
+	var err error
+	seq := func(yield func(int64, int64) bool) {
+		err = scanPairs(r, opts, yield)
+	}
+	return seq, func() error { return err }
+}
+
+// PairsFile is Pairs for a named input, opened as ParseColumns opens it when the
+// iteration starts and closed when it ends, including on an early break.
+func PairsFile(filename string, opts ParseOptions) (iter.Seq2[int64, int64], func() error) {
+	var err error
+	seq := func(yield func(int64, int64) bool) {
+		src, openErr := openSource(filename, opts)
+		if openErr != nil {
+			err = openErr
+			return
+		}
+		defer src.Close()
+		err = scanPairs(src, opts, yield)
+	}
+	return seq, func() error { return err }
+}
+
+// scanPairs parses r, passing each pair to yield until it returns false, and
+// returns the error that stopped the scan early.
+func scanPairs(r io.Reader, opts ParseOptions, yield func(int64, int64) bool) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	scanner := input.NewScanner(r)
+	parser := newLineParser(opts)
+	lineNo := 0
+	for (opts.HeadLines == 0 || lineNo-opts.SkipLines < opts.HeadLines) && scanner.Scan() {
+		lineNo++
+		if lineNo <= opts.SkipLines {
+			continue
+		}
+		line := scanner.Bytes()
+		if lineNo == 1 {
+			line = input.TrimBOM(line)
+			if isUTF16, _ := input.SniffUTF16(line); isUTF16 {
+				return &lineError{line: lineNo, err: errUTF16}
+			}
+			if parser.isHeader(line) {
+				continue
+			}
+		}
+		if parser.isComment(line) || opts.SkipLine != nil && opts.SkipLine(lineNo, string(bytes.TrimSuffix(line, []byte{'\r'}))) {
+			continue
+		}
+
+		left, right, err := parser.parse(line)
+		if err != nil {
+			var missing *missingThirdError
+			if opts.Strict || errors.As(err, &missing) {
+				return parser.locate(lineNo, line, err, parser.bad)
+			}
+			continue
+		}
+		if opts.Timestamped && parser.third < opts.Since {
+			continue
+		}
+		if opts.NonNegative && (left < 0 || right < 0) {
+			return parser.negative(lineNo, line, left)
+		}
+		if !yield(left, right) {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return input.ScanError(err, lineNo+1)
+	}
+	return nil
+}
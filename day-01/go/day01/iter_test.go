@@ -0,0 +1,102 @@
+package day01
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPairs(t *testing.T) {
+	var got [][2]int64
+	pairs, pairsErr := Pairs(strings.NewReader("left right\n"+example+"x y\n"), ParseOptions{})
+	for left, right := range pairs {
+		got = append(got, [2]int64{left, right})
+	}
+	if err := pairsErr(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]int64{{3, 4}, {4, 3}, {2, 5}, {1, 3}, {3, 9}, {3, 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("pairs = %v, want %v", got, want)
+	}
+
+	// A strict failure ends the loop and is reported afterwards
+	got = got[:0]
+	pairs, pairsErr = Pairs(strings.NewReader("3 4\n4 x\n2 5\n"), ParseOptions{Strict: true})
+	for left, right := range pairs {
+		got = append(got, [2]int64{left, right})
+	}
+	if err := pairsErr(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("pairsErr = %v, want the error on line 2", err)
+	}
+	if !slices.Equal(got, want[:1]) {
+		t.Errorf("pairs before the error = %v, want %v", got, want[:1])
+	}
+}
+
+// openFiles counts the file descriptors open in this process.
+func openFiles(t *testing.T) int {
+	t.Helper()
+
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("no /proc/self/fd to count open files")
+	}
+	return len(entries)
+}
+
+func TestPairsFileBreak(t *testing.T) {
+	path := writeInput(t, example)
+	before := openFiles(t)
+
+	pairs, pairsErr := PairsFile(path, ParseOptions{})
+	n := 0
+	for range pairs {
+		if n++; n == 1 {
+			if during := openFiles(t); during != before+1 {
+				t.Fatalf("%d files open during the loop, want %d", during, before+1)
+			}
+		}
+		if n == 2 {
+			break
+		}
+	}
+	if err := pairsErr(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("loop ran %d times, want to break after 2", n)
+	}
+	if after := openFiles(t); after != before {
+		t.Errorf("%d files open after the break, want the %d from before", after, before)
+	}
+
+	pairs, pairsErr = PairsFile(path+".missing", ParseOptions{})
+	for range pairs {
+		t.Fatal("PairsFile yielded pairs from a missing file")
+	}
+	if err := pairsErr(); err == nil {
+		t.Error("pairsErr = nil for a missing file")
+	}
+}
+
+func TestPairsNegative(t *testing.T) {
+	opts := ParseOptions{NonNegative: true}
+	for _, content := range []string{"3 4\n-1 5\n", "3 4\n1  -5\n"} {
+		pairs, pairsErr := Pairs(strings.NewReader(content), opts)
+		for range pairs {
+		}
+		_, want := ParseColumns(writeInput(t, content), opts)
+		got := pairsErr()
+		if got == nil || want == nil || got.Error() != want.Error() {
+			t.Errorf("%q: Pairs error = %v, want the ParseColumns error %v", content, got, want)
+			continue
+		}
+		gotCtx, _ := ErrorContext(got)
+		wantCtx, ok := ErrorContext(want)
+		if !ok || gotCtx != wantCtx {
+			t.Errorf("%q: Pairs context = %+v, want %+v", content, gotCtx, wantCtx)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build race
+
+package day01
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// settledGoroutines waits for goroutines winding down after a solve, returning
+// the count once it is at most want or a second has passed.
+func settledGoroutines(want int) int {
+	deadline := time.Now().Add(time.Second)
+	n := runtime.NumGoroutine()
+	for n > want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		n = runtime.NumGoroutine()
+	}
+	return n
+}
+
+func TestNoGoroutineLeaks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, example)
+	}))
+	defer srv.Close()
+	path := writeInput(t, example+"x y\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	solves := []struct {
+		name  string
+		solve func() error
+	}{
+		{name: "sequential", solve: func() error { _, err := SolveFile(path, ParseOptions{}); return err }},
+		{name: "parallel", solve: func() error { _, err := SolveFile(path, ParseOptions{Workers: 4}); return err }},
+		{name: "parallel strict failure", solve: func() error {
+			SolveFile(path, ParseOptions{Workers: 4, Strict: true})
+			return nil
+		}},
+		{name: "cancelled", solve: func() error {
+			ParseColumnsContext(ctx, path, ParseOptions{Workers: 4})
+			return nil
+		}},
+		{name: "mmap", solve: func() error { _, err := SolveFile(path, ParseOptions{Mmap: true}); return err }},
+		{name: "url", solve: func() error { _, err := SolveFile(srv.URL, ParseOptions{}); return err }},
+		{name: "pairs break", solve: func() error {
+			pairs, pairsErr := PairsFile(srv.URL, ParseOptions{})
+			for range pairs {
+				break
+			}
+			return pairsErr()
+		}},
+	}
+	for _, tt := range solves {
+		t.Run(tt.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			if err := tt.solve(); err != nil {
+				t.Fatal(err)
+			}
+			if after := settledGoroutines(before); after > before {
+				buf := make([]byte, 1<<16)
+				t.Errorf("%d goroutines before the solve, %d after:\n%s", before, after, buf[:runtime.Stack(buf, true)])
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package day01
+
+import (
+	"bytes"
+	"errors"
+)
+
+// errMmapUnsupported reports a platform or file that cannot be memory-mapped.
+var errMmapUnsupported = errors.New("memory mapping not supported")
+
+// mappedLines splits a memory-mapped input into lines in place, trimming the
+// terminator like bufio.ScanLines. Lines alias the mapping, so they must be
+// copied before it is unmapped.
+type mappedLines struct {
+	data []byte
+	line []byte
+}
+
+func (m *mappedLines) Scan() bool {
+	if len(m.data) == 0 {
+		return false
+	}
+	i := bytes.IndexByte(m.data, '\n')
+	if i < 0 {
+		m.line, m.data = m.data, nil
+	} else {
+		m.line, m.data = m.data[:i], m.data[i+1:]
+	}
+	m.line = bytes.TrimSuffix(m.line, []byte{'\r'})
+	return true
+}
+
+func (m *mappedLines) Bytes() []byte { return m.line }
+
+// Err always returns nil: a mapped file is already in memory.
+func (m *mappedLines) Err() error { return nil }
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package day01
+
+import "os"
+
+// mapFile always fails on platforms without syscall.Mmap, so the file is read
+// with buffered IO.
+func mapFile(file *os.File) ([]byte, func() error, error) {
+	return nil, nil, errMmapUnsupported
+}
@@ -0,0 +1,61 @@
+package day01
+
+import (
+	"bufio"
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMappedLines(t *testing.T) {
+	for _, data := range []string{"", "\n", "3 4\n4 3\n", "3 4\r\n4 3", "a\n\nb\r\n\r\n", "last line"} {
+		var got []string
+		lines := &mappedLines{data: []byte(data)}
+		for lines.Scan() {
+			got = append(got, string(lines.Bytes()))
+		}
+		var want []string
+		scanner := bufio.NewScanner(strings.NewReader(data))
+		for scanner.Scan() {
+			want = append(want, scanner.Text())
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("mappedLines(%q) = %q, want bufio.ScanLines' %q", data, got, want)
+		}
+	}
+}
+
+func TestMmap(t *testing.T) {
+	var generated bytes.Buffer
+	if err := Generate(&generated, 5000, 2); err != nil {
+		t.Fatal(err)
+	}
+	inputs := []string{example, "3 4\r\n4 3\r\nx y\r\n# note\n1 1", "", generated.String()}
+	for _, input := range inputs {
+		path := writeInput(t, input)
+		opts := ParseOptions{Comment: '#', SkipSamples: 5}
+		buffered, err := ParseColumns(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Mmap = true
+		mapped, err := ParseColumns(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(mapped.Left, buffered.Left) || !slices.Equal(mapped.Right, buffered.Right) {
+			t.Errorf("%d-byte input: mapped columns differ from buffered ones", len(input))
+		}
+		if mapped.Lines != buffered.Lines || mapped.Skipped != buffered.Skipped || mapped.Comments != buffered.Comments {
+			t.Errorf("%d-byte input: mapped counts %d, %d, %d, want %d, %d, %d", len(input),
+				mapped.Lines, mapped.Skipped, mapped.Comments, buffered.Lines, buffered.Skipped, buffered.Comments)
+		}
+		// Samples are copied out of the mapping, which is gone by now
+		for i, skipped := range mapped.SkippedLines {
+			if skipped.Text != buffered.SkippedLines[i].Text {
+				t.Errorf("skipped line %d = %q, want %q", skipped.Line, skipped.Text, buffered.SkippedLines[i].Text)
+			}
+		}
+	}
+}
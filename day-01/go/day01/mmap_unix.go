@@ -0,0 +1,26 @@
+//go:build unix
+
+package day01
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the whole of file read-only, returning the mapping and a function
+// releasing it. Empty and non-regular files cannot be mapped.
+func mapFile(file *os.File) ([]byte, func() error, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() || info.Size() == 0 || int64(int(info.Size())) != info.Size() {
+		return nil, nil, errMmapUnsupported
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,61 @@
+package day01
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Pair is one left/right pairing from the sorted lockstep walk, with its
+// contribution to the total distance.
+type Pair struct {
+	Left     int64
+	Right    int64
+	Distance int64
+}
+
+// TopPairs returns the n pairs contributing the most distance, largest first.
+// Pairs of equal distance keep their sorted order. Both slices are sorted in place,
+// so it can reuse the slices already sorted by NewResult or SolveBoth.
+// Time Complexity: O(n log n) dominated by sorting
+// Space Complexity: O(n) for the pair list
+func TopPairs(left, right []int64, n int) []Pair {
+	SortColumns(left, right)
+
+	pairs := make([]Pair, len(left))
+	for i := range left {
+		diff := left[i] - right[i]
+		if diff < 0 {
+			diff = -diff
+		}
+		pairs[i] = Pair{Left: left[i], Right: right[i], Distance: diff}
+	}
+
+	slices.SortStableFunc(pairs, func(a, b Pair) int {
+		return cmp.Compare(b.Distance, a.Distance)
+	})
+	return pairs[:min(max(n, 0), len(pairs))]
+}
+
+// Contribution is one left value's share of the similarity score, with the
+// running total after it is added.
+type Contribution struct {
+	Left      int64
+	Frequency int64
+	Product   int64
+	Total     int64
+}
+
+// ExplainSimilarity breaks the similarity score down into the contribution of
+// each left value, in input order. The last Total is the score.
+// Time Complexity: O(n)
+// Space Complexity: O(n) for the contributions
+func ExplainSimilarity(left []int64, rightFreq map[int64]int64) []Contribution {
+	contributions := make([]Contribution, len(left))
+	var total int64
+	for i, leftNum := range left {
+		freq := rightFreq[leftNum]
+		total += leftNum * freq
+		contributions[i] = Contribution{Left: leftNum, Frequency: freq, Product: leftNum * freq, Total: total}
+	}
+	return contributions
+}
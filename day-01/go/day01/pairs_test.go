@@ -0,0 +1,22 @@
+package day01
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExplainSimilarity(t *testing.T) {
+	left := []int64{3, 4, 2, 1, 3, 3}
+	right := []int64{4, 3, 5, 3, 9, 3}
+	want := []Contribution{
+		{Left: 3, Frequency: 3, Product: 9, Total: 9},
+		{Left: 4, Frequency: 1, Product: 4, Total: 13},
+		{Left: 2, Frequency: 0, Product: 0, Total: 13},
+		{Left: 1, Frequency: 0, Product: 0, Total: 13},
+		{Left: 3, Frequency: 3, Product: 9, Total: 22},
+		{Left: 3, Frequency: 3, Product: 9, Total: 31},
+	}
+	if got := ExplainSimilarity(left, Frequencies(right)); !slices.Equal(got, want) {
+		t.Errorf("ExplainSimilarity = %v, want %v", got, want)
+	}
+}
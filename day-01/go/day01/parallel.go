@@ -0,0 +1,124 @@
+package day01
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// readColumnsParallel splits file into newline-aligned byte ranges, parses each
+// range in its own goroutine and merges the partial columns in file order.
+// Time Complexity: O(n / workers) wall time for parsing, O(n) to merge
+// Space Complexity: O(n) for both columns
+func readColumnsParallel(ctx context.Context, file *os.File, opts ParseOptions) (*Columns, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %v", err)
+	}
+
+	bounds, err := chunkBounds(file, info.Size(), opts.Workers)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %v", err)
+	}
+
+	type chunkResult struct {
+		cols *Columns
+		err  error
+	}
+
+	// Parse every chunk concurrently, each result lands in its own slot
+	results := make([]chunkResult, len(bounds)-1)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			section := io.NewSectionReader(file, bounds[i], bounds[i+1]-bounds[i])
+			chunkOpts := opts
+			chunkOpts.continued = i > 0
+			cols, err := scanColumns(ctx, section, chunkOpts)
+			results[i] = chunkResult{cols: cols, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	// Merge in file order, translating chunk-relative line numbers on error. A
+	// failed chunk ends the merge, keeping the lines parsed before the failure.
+	parts := make([]*Columns, 0, len(results))
+	lineOffset := 0
+	for _, res := range results {
+		if res.err != nil {
+			if res.cols != nil {
+				parts = append(parts, res.cols)
+			}
+			err := res.err
+			if lineErr, ok := err.(*lineError); ok {
+				translated := *lineErr
+				translated.line += lineOffset
+				err = &translated
+			}
+			return MergeColumns(parts...), err
+		}
+		for i := range res.cols.SkippedLines {
+			res.cols.SkippedLines[i].Line += lineOffset
+		}
+		parts = append(parts, res.cols)
+		lineOffset += res.cols.Lines
+	}
+	merged := MergeColumns(parts...)
+	// Every chunk kept its own samples; keep the first ones of the whole file
+	merged.SkippedLines = merged.SkippedLines[:min(len(merged.SkippedLines), opts.SkipSamples)]
+
+	if err := merged.validate(opts); err != nil {
+		return merged, err
+	}
+	return merged, nil
+}
+
+// chunkBounds returns the start offsets of up to n byte ranges covering size bytes,
+// followed by size itself. Every interior boundary sits just past a newline so no
+// line is split between two chunks.
+func chunkBounds(r io.ReaderAt, size int64, n int) ([]int64, error) {
+	bounds := []int64{0}
+	for i := 1; i < n; i++ {
+		offset := size * int64(i) / int64(n)
+		if offset <= bounds[len(bounds)-1] {
+			continue
+		}
+
+		// Advance to the byte after the next newline at or after offset-1
+		next, err := nextLineStart(r, offset-1, size)
+		if err != nil {
+			return nil, err
+		}
+		if next > bounds[len(bounds)-1] && next < size {
+			bounds = append(bounds, next)
+		}
+	}
+	return append(bounds, size), nil
+}
+
+// nextLineStart returns the offset just past the first newline at or after offset,
+// or size when no newline follows.
+func nextLineStart(r io.ReaderAt, offset, size int64) (int64, error) {
+	reader := bufio.NewReader(io.NewSectionReader(r, offset, size-offset))
+	line, err := reader.ReadSlice('\n')
+	for err == bufio.ErrBufferFull {
+		offset += int64(len(line))
+		line, err = reader.ReadSlice('\n')
+	}
+	if err == io.EOF {
+		return size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		return size, nil
+	}
+	return offset + int64(len(line)), nil
+}
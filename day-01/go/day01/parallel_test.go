@@ -0,0 +1,43 @@
+package day01
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParallelMatchesSequential(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Generate(&buf, 20_000, 7); err != nil {
+		t.Fatal(err)
+	}
+	// Spread malformed lines through the file so they land in different chunks
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, bad := range []string{"oops\n", "\n", "1 2 3\n", "4.5 6.5\n"} {
+		at := (i + 1) * len(lines) / 5
+		lines = slices.Insert(lines, at, bad)
+	}
+	path := writeInput(t, strings.Join(lines, ""))
+
+	want, err := ParseColumns(path, ParseOptions{})
+	if err != nil {
+		t.Fatalf("sequential ParseColumns: %v", err)
+	}
+	for _, workers := range []int{2, 3, 8} {
+		got, err := ParseColumns(path, ParseOptions{Workers: workers})
+		if err != nil {
+			t.Fatalf("ParseColumns with %d workers: %v", workers, err)
+		}
+		if !slices.Equal(got.Left, want.Left) || !slices.Equal(got.Right, want.Right) {
+			t.Errorf("%d workers: columns differ from the sequential parse", workers)
+		}
+		if got.Lines != want.Lines || got.Skipped != want.Skipped {
+			t.Errorf("%d workers: %d lines, %d skipped, want %d, %d", workers, got.Lines, got.Skipped, want.Lines, want.Skipped)
+		}
+		if TotalDistance(got.Left, got.Right) != TotalDistance(want.Left, want.Right) ||
+			SimilarityScore(got.Left, got.Right) != SimilarityScore(want.Left, want.Right) {
+			t.Errorf("%d workers: answers differ from the sequential parse", workers)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package day01
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"aoc-2024/internal/input"
+)
+
+// lineParser splits and parses input lines without allocating on the happy path.
+// The fields scratch slice is reused across lines and aliases the current line;
+// cleaned holds a value with its separators stripped when opts.Clean is set, and
+// third the third value of the last line parsed, its weight or timestamp, when
+// opts.Weighted or opts.Timestamped is set.
+// leftAt and rightAt are the byte offsets of the selected fields in the last line,
+// and bad that of the token behind its parse error.
+type lineParser struct {
+	opts            ParseOptions
+	fields          [][]byte
+	cleaned         []byte
+	third           int64
+	leftAt, rightAt int
+	bad             int
+}
+
+func newLineParser(opts ParseOptions) *lineParser {
+	return &lineParser{opts: opts, fields: make([][]byte, 0, 4)}
+}
+
+// partialPairError reports a two-field line where only one value is a valid integer.
+// The value that did parse is kept so the damage shows up as a column imbalance.
+type partialPairError struct {
+	line   string
+	field  string
+	leftOK bool
+	value  int64
+}
+
+func (e *partialPairError) Error() string {
+	if e.leftOK {
+		return invalidNumber("right", e.field, e.line)
+	}
+	return invalidNumber("left", e.field, e.line)
+}
+
+// fieldCountError reports a line without the expected number of fields; got is zero for a
+// blank or whitespace-only line.
+type fieldCountError struct {
+	line string
+	got  int
+	// atLeast is set when fields are selected by index, so extra fields are fine
+	// and want is the minimum needed.
+	want    int
+	atLeast bool
+}
+
+func (e *fieldCountError) Error() string {
+	if e.atLeast {
+		return fmt.Sprintf("expected at least %d fields, got %d: %q", e.want, e.got, e.line)
+	}
+	return fmt.Sprintf("expected %d fields, got %d: %q", e.want, e.got, e.line)
+}
+
+// missingThirdError reports a two-field line in Weighted or Timestamped mode. It
+// fails the parse even when malformed lines are skipped, since it usually means
+// the whole input lacks the third column.
+type missingThirdError struct {
+	line string
+	// field names the missing value, "weight" or "timestamp".
+	field string
+}
+
+func (e *missingThirdError) Error() string {
+	return fmt.Sprintf("missing %s in the third field: %q", e.field, e.line)
+}
+
+// rangeError reports a line holding a value above ParseOptions.MaxValue.
+type rangeError struct {
+	line       string
+	value, max int64
+}
+
+func (e *rangeError) Error() string {
+	return fmt.Sprintf("value %d exceeds the maximum %d: %q", e.value, e.max, e.line)
+}
+
+// invalidNumber describes why field, the named side of line, is not a valid
+// integer, calling out float and scientific notation values specifically.
+func invalidNumber(side, field, line string) string {
+	if strings.ContainsAny(field, ".eE") {
+		if _, err := strconv.ParseFloat(field, 64); err == nil {
+			return fmt.Sprintf("value %s is not an integer", field)
+		}
+	}
+	return fmt.Sprintf("invalid %s number: %q", side, line)
+}
+
+// parse parses a line holding exactly two integers, or three in Weighted or
+// Timestamped mode, ignoring a trailing \r. Values are parsed as int64 so large
+// inputs behave the same on 32-bit builds.
+func (p *lineParser) parse(line []byte) (int64, int64, error) {
+	// Drop the carriage return left behind by Windows CRLF line endings
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+
+	p.fields = p.opts.appendFields(p.fields[:0], line)
+	leftField, rightField, err := p.selectFields(line)
+	if err != nil {
+		return 0, 0, err
+	}
+	p.leftAt, p.rightAt = offsetIn(line, leftField), offsetIn(line, rightField)
+
+	leftNum, leftOK := p.value(leftField)
+	rightNum, rightOK := p.value(rightField)
+	switch {
+	case !leftOK && !rightOK:
+		p.bad = p.leftAt
+		return 0, 0, errors.New(invalidNumber("left", string(leftField), string(line)))
+	case !leftOK:
+		p.bad = p.leftAt
+		return 0, 0, &partialPairError{line: string(line), field: string(leftField), value: rightNum}
+	case !rightOK:
+		p.bad = p.rightAt
+		return 0, 0, &partialPairError{line: string(line), field: string(rightField), leftOK: true, value: leftNum}
+	}
+	thirdAt := len(line)
+	if field := p.opts.thirdField(); field != "" {
+		thirdAt = offsetIn(line, p.fields[2])
+		third, ok := p.value(p.fields[2])
+		if !ok {
+			p.bad = thirdAt
+			return 0, 0, errors.New(invalidNumber(field, string(p.fields[2]), string(line)))
+		}
+		p.third = third
+	}
+	if p.opts.MaxValue != 0 {
+		// A weight is bounded like the values, a timestamp is not
+		checked := 2
+		if p.opts.Weighted {
+			checked = 3
+		}
+		values := [...]int64{leftNum, rightNum, p.third}
+		for i, v := range values[:checked] {
+			if v > p.opts.MaxValue {
+				p.bad = [...]int{p.leftAt, p.rightAt, thirdAt}[i]
+				return 0, 0, &rangeError{line: string(line), value: v, max: p.opts.MaxValue}
+			}
+		}
+	}
+	return leftNum, rightNum, nil
+}
+
+// offsetIn returns the byte offset of field within line, which it aliases as the
+// splitters guarantee, or the end of line for an empty field.
+func offsetIn(line, field []byte) int {
+	if len(field) == 0 {
+		return len(line)
+	}
+	return cap(line) - cap(field)
+}
+
+// locate wraps err, the failure on line lineNo, with the line's text and the byte
+// offset of the offending token for ErrorContext.
+func (p *lineParser) locate(lineNo int, line []byte, err error, column int) error {
+	text := bytes.TrimSuffix(line, []byte{'\r'})
+	return &lineError{line: lineNo, err: err, located: true, text: string(text), column: min(column, len(text))}
+}
+
+// negative reports a NonNegative violation on line lineNo, pointing at the left
+// value when it is the negative one and at the right value otherwise.
+func (p *lineParser) negative(lineNo int, line []byte, left int64) error {
+	column := p.leftAt
+	if left >= 0 {
+		column = p.rightAt
+	}
+	return p.locate(lineNo, line, fmt.Errorf("negative value: %q", line), column)
+}
+
+// selectFields picks the left and right fields of line from p.fields: exactly two
+// fields by default, three with the weight or timestamp last in Weighted or
+// Timestamped mode, or the configured FieldIndices out of any number of fields.
+func (p *lineParser) selectFields(line []byte) ([]byte, []byte, error) {
+	indices := p.opts.FieldIndices
+	if indices == nil {
+		want := 2
+		if field := p.opts.thirdField(); field != "" {
+			if len(p.fields) == 2 {
+				p.bad = len(line)
+				return nil, nil, &missingThirdError{line: string(line), field: field}
+			}
+			want = 3
+		}
+		if len(p.fields) != want {
+			// Point at the first extra field, or at the end where one is missing
+			p.bad = len(line)
+			if len(p.fields) > want {
+				p.bad = offsetIn(line, p.fields[want])
+			}
+			return nil, nil, &fieldCountError{line: string(line), got: len(p.fields), want: want}
+		}
+		return p.fields[0], p.fields[1], nil
+	}
+
+	want := max(indices[0], indices[1]) + 1
+	if len(p.fields) < want {
+		p.bad = len(line)
+		return nil, nil, &fieldCountError{line: string(line), got: len(p.fields), want: want, atLeast: true}
+	}
+	return p.fields[indices[0]], p.fields[indices[1]], nil
+}
+
+// value parses one field in the configured base, first stripping digit-grouping
+// separators when opts.Clean is set.
+func (p *lineParser) value(field []byte) (int64, bool) {
+	if !p.opts.Clean {
+		return p.parseInt(field)
+	}
+
+	p.cleaned = p.cleaned[:0]
+	for _, c := range field {
+		if c != '_' && c != ',' {
+			p.cleaned = append(p.cleaned, c)
+		}
+	}
+	return p.parseInt(p.cleaned)
+}
+
+// parseInt parses field in opts.Base, keeping the allocation-free decimal path for
+// the default base.
+func (p *lineParser) parseInt(field []byte) (int64, bool) {
+	if p.opts.Base == 0 || p.opts.Base == 10 {
+		return input.ParseInt(field)
+	}
+	return input.ParseIntBase(field, p.opts.Base)
+}
+
+// isHeader reports whether line looks like a row of column names, such as
+// "left right": at least two fields, none of them a number. A comment line is
+// never a header, so it is counted as a comment wherever it appears.
+func (p *lineParser) isHeader(line []byte) bool {
+	if p.isComment(line) {
+		return false
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	p.fields = p.opts.appendFields(p.fields[:0], line)
+	if len(p.fields) < 2 {
+		return false
+	}
+	for _, field := range p.fields {
+		if _, ok := p.value(field); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isComment reports whether line starts, after any leading whitespace, with the
+// configured comment character.
+func (p *lineParser) isComment(line []byte) bool {
+	if p.opts.Comment == 0 {
+		return false
+	}
+	line = bytes.TrimLeftFunc(line, unicode.IsSpace)
+	r, _ := utf8.DecodeRune(line)
+	return len(line) > 0 && r == p.opts.Comment
+}
+
+// appendFields appends the fields of line to dst using the configured delimiter.
+// Whitespace mode behaves like strings.Fields for ASCII whitespace; delimiter mode
+// splits on the exact delimiter and trims surrounding whitespace from each field.
+func (o ParseOptions) appendFields(dst [][]byte, line []byte) [][]byte {
+	if o.Delimiter == "" {
+		return input.AppendFields(dst, line)
+	}
+
+	// Treat blank lines like the whitespace splitter does
+	if len(bytes.TrimSpace(line)) == 0 {
+		return dst
+	}
+	delim := o.Delimiter[0]
+	for {
+		i := bytes.IndexByte(line, delim)
+		if i < 0 {
+			return append(dst, bytes.TrimSpace(line))
+		}
+		dst = append(dst, bytes.TrimSpace(line[:i]))
+		line = line[i+1:]
+	}
+}
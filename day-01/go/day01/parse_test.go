@@ -0,0 +1,633 @@
+package day01
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// solveString solves input with opts, failing the test on any error.
+func solveString(t *testing.T, input string, opts ParseOptions) *Result {
+	t.Helper()
+
+	res, err := Solve(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	return res
+}
+
+func TestDelimiters(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		input     string
+	}{
+		{name: "comma", delimiter: ",", input: "3,4\n4,3\n2,5\n1,3\n3,9\n3,3\n"},
+		{name: "comma with spaces", delimiter: ",", input: "3 , 4\n 4,3\n2,  5\n1,3 \n3,9\n3,3\n"},
+		{name: "tab", delimiter: "\t", input: "3\t4\n4\t3\n2\t5\n1\t3\n3\t9\n3\t3\n"},
+	}
+	want := solveString(t, example, ParseOptions{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := solveString(t, tt.input, ParseOptions{Delimiter: tt.delimiter})
+			if res.TotalDistance != want.TotalDistance || res.SimilarityScore != want.SimilarityScore {
+				t.Errorf("answers = %d, %d, want %d, %d as for spaces", res.TotalDistance, res.SimilarityScore, want.TotalDistance, want.SimilarityScore)
+			}
+			if res.LinesSkipped != 0 {
+				t.Errorf("LinesSkipped = %d, want 0", res.LinesSkipped)
+			}
+		})
+	}
+}
+
+// parseFields is the strings.Fields parser the byte-level lineParser replaced.
+func parseFields(line string) (int64, int64, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	left, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	right, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return left, right, true
+}
+
+func TestParseMatchesFields(t *testing.T) {
+	lines := []string{
+		"3   4",
+		"3 4",
+		"3\t4",
+		"  3 \t  4  ",
+		"\t\t10\t\t20\t",
+		"-5 +7",
+		"9223372036854775807 -9223372036854775808",
+		"9223372036854775808 1",
+		"1 2 3",
+		"1",
+		"",
+		"   ",
+		"a b",
+		"1 b",
+		"0042 042",
+		"1\v2",
+		"1\f2\r",
+	}
+	for _, line := range lines {
+		left, right, err := newLineParser(ParseOptions{}).parse([]byte(line))
+		wantLeft, wantRight, ok := parseFields(line)
+		if (err == nil) != ok || left != wantLeft || right != wantRight {
+			t.Errorf("parse(%q) = %d, %d, %v; strings.Fields gives %d, %d, ok %v", line, left, right, err, wantLeft, wantRight, ok)
+		}
+	}
+}
+
+func FuzzParseLine(f *testing.F) {
+	for _, line := range []string{"3   4", "12345   67890\r", "+1 -2", "١ ٢", "1\x002 3", "1_000 2,000"} {
+		f.Add([]byte(line))
+	}
+	variants := []ParseOptions{{}, {Clean: true}, {Delimiter: ","}, {Base: 16}, {Weighted: true}}
+	f.Fuzz(func(t *testing.T, line []byte) {
+		for _, opts := range variants {
+			p := newLineParser(opts)
+			left, right, err := p.parse(line)
+			if err != nil {
+				// A skipped line must still locate its bad token within the line
+				if p.bad < 0 || p.bad > len(line) {
+					t.Errorf("opts %+v: error offset %d outside line %q", opts, p.bad, line)
+				}
+				continue
+			}
+			if opts.Delimiter == "" && !opts.Clean && opts.Base == 0 && !opts.Weighted {
+				wantLeft, wantRight, ok := parseFields(string(line))
+				if !ok || left != wantLeft || right != wantRight {
+					t.Errorf("parse(%q) = %d, %d; strings.Fields gives %d, %d, ok %v", line, left, right, wantLeft, wantRight, ok)
+				}
+			}
+		}
+	})
+}
+
+func TestCRLF(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  ParseOptions
+	}{
+		{name: "whitespace", input: example},
+		{name: "strict", input: example, opts: ParseOptions{Strict: true}},
+		{name: "comma", input: strings.ReplaceAll(example, "   ", ","), opts: ParseOptions{Delimiter: ",", Strict: true}},
+		{name: "tab", input: strings.ReplaceAll(example, "   ", "\t"), opts: ParseOptions{Delimiter: "\t", Strict: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lf := solveString(t, tt.input, tt.opts)
+			crlf := solveString(t, strings.ReplaceAll(tt.input, "\n", "\r\n"), tt.opts)
+			if crlf.SimilarityScore != lf.SimilarityScore || crlf.TotalDistance != lf.TotalDistance {
+				t.Errorf("CRLF answers = %d, %d, LF answers = %d, %d", crlf.TotalDistance, crlf.SimilarityScore, lf.TotalDistance, lf.SimilarityScore)
+			}
+			if crlf.SimilarityScore != 31 || crlf.LinesSkipped != 0 {
+				t.Errorf("CRLF similarity %d with %d lines skipped, want 31 with none", crlf.SimilarityScore, crlf.LinesSkipped)
+			}
+		})
+	}
+}
+
+func TestComments(t *testing.T) {
+	const input = "# a first-line comment, not a header\n3   4\n# a comment\n4   3\n  # indented, with numbers: 1 2\n2   5\n1   3\n#9 9\n3   9\n3   3\n"
+	cols, err := ReadColumns(strings.NewReader(input), ParseOptions{Comment: '#'})
+	if err != nil {
+		t.Fatalf("ReadColumns: %v", err)
+	}
+	if cols.Comments != 4 || cols.Skipped != 0 || cols.Headers != 0 {
+		t.Errorf("%d comments, %d skipped and %d headers, want 4, 0 and 0", cols.Comments, cols.Skipped, cols.Headers)
+	}
+	if got := SimilarityScore(cols.Left, cols.Right); got != 31 {
+		t.Errorf("SimilarityScore = %d, want 31", got)
+	}
+	if res := NewResult(cols, Frequencies(cols.Right)); res.LinesParsed != 6 || res.LinesSkipped != 0 {
+		t.Errorf("LinesParsed = %d, LinesSkipped = %d, want 6 and 0", res.LinesParsed, res.LinesSkipped)
+	}
+}
+
+func TestNotInteger(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "trailing .0", input: "3 4\n42.0 5\n", want: "line 2: value 42.0 is not an integer"},
+		{name: "scientific notation", input: "3 4\n1 1\n6 1e3\n", want: "line 3: value 1e3 is not an integer"},
+		{name: "neither int nor float", input: "3 4\n4.2.0 1\n", want: `line 2: invalid left number: "4.2.0 1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Solve(strings.NewReader(tt.input), ParseOptions{Strict: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Solve error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "underscores", input: "3_000 4_000\n4_000 3_000\n2_000 5_000\n1_000 3_000\n3_000 9_000\n3_000 3_000\n"},
+		{name: "commas", input: "3,000 4,000\n4,000 3,000\n2,000 5,000\n1,000 3,000\n3,000 9,000\n3,000 3,000\n"},
+		{name: "mixed", input: "3_000 4,000\n4,000 3_000\n2000 5,000\n1_000 3000\n3,000 9_000\n3000 3,000\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := solveString(t, tt.input, ParseOptions{Clean: true, Strict: true})
+			// The example scaled by 1000; each similarity term scales with its value
+			if res.TotalDistance != 11_000 || res.SimilarityScore != 31_000 {
+				t.Errorf("answers = %d, %d, want 11000, 31000", res.TotalDistance, res.SimilarityScore)
+			}
+			if _, err := Solve(strings.NewReader(tt.input), ParseOptions{Strict: true}); err == nil {
+				t.Error("grouped values parsed without Clean")
+			}
+		})
+	}
+
+	opts := ParseOptions{Clean: true, Delimiter: ","}
+	if err := opts.Validate(); err == nil || !strings.Contains(err.Error(), "comma delimiter") {
+		t.Errorf("Validate = %v, want Clean rejected with the comma delimiter", err)
+	}
+	if _, err := Solve(strings.NewReader(example), opts); err == nil {
+		t.Error("Solve accepted Clean with the comma delimiter")
+	}
+}
+
+func TestSkipCounts(t *testing.T) {
+	const input = "3 4\n\n4 3\n   \t\n2 5 8\n1 3\nx y\n3 9\n3 3\n"
+	for name, opts := range map[string]ParseOptions{"sequential": {}, "parallel": {Workers: 3}} {
+		t.Run(name, func(t *testing.T) {
+			cols, err := ParseColumns(writeInput(t, input), opts)
+			if err != nil {
+				t.Fatalf("ParseColumns: %v", err)
+			}
+			want := SkipCounts{Blank: 2, FieldCount: 1, NotInteger: 1}
+			if cols.Skips != want {
+				t.Errorf("Skips = %+v, want %+v", cols.Skips, want)
+			}
+			if cols.Skipped != 4 {
+				t.Errorf("Skipped = %d, want 4", cols.Skipped)
+			}
+		})
+	}
+
+	cols, err := ReadColumns(strings.NewReader("1 2\n5 9999\n"), ParseOptions{MaxValue: 100})
+	if err != nil {
+		t.Fatalf("ReadColumns: %v", err)
+	}
+	if want := (SkipCounts{OutOfRange: 1}); cols.Skips != want {
+		t.Errorf("Skips with MaxValue = %+v, want %+v", cols.Skips, want)
+	}
+}
+
+func TestLineWindow(t *testing.T) {
+	// The example sits between two lines before and two after that change both answers
+	input := "100 1\n200 2\n" + example + "70 7\n80 8\n"
+	tests := []struct {
+		name        string
+		skip, head  int
+		distance    int64
+		similarity  int64
+		linesParsed int
+	}{
+		{name: "example window", skip: 2, head: 6, distance: 11, similarity: 31, linesParsed: 6},
+		{name: "skip only", skip: 8, distance: 63 + 72, linesParsed: 2},
+		{name: "head only", head: 1, distance: 99, linesParsed: 1},
+		{name: "head past the end", skip: 9, head: 5, distance: 72, linesParsed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, opts := range map[string]ParseOptions{
+				"buffered": {SkipLines: tt.skip, HeadLines: tt.head},
+				"parallel": {SkipLines: tt.skip, HeadLines: tt.head, Workers: 4},
+			} {
+				res, err := SolveFile(writeInput(t, input), opts)
+				if err != nil {
+					t.Fatalf("%s: SolveFile: %v", name, err)
+				}
+				if res.TotalDistance != tt.distance || res.SimilarityScore != tt.similarity || res.LinesParsed != tt.linesParsed {
+					t.Errorf("%s: answers = %d, %d over %d lines, want %d, %d over %d",
+						name, res.TotalDistance, res.SimilarityScore, res.LinesParsed, tt.distance, tt.similarity, tt.linesParsed)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldIndices(t *testing.T) {
+	// Fields 1 and 3 hold the example's pairs; 0 and 2 are ids and noise
+	const input = "a 3 x 4\nb 4 y 3\nc 2 z 5\nd 1 w 3\ne 3 v 9\nf 3 u 3\n"
+	res := solveString(t, input, ParseOptions{FieldIndices: []int{1, 3}, Strict: true})
+	if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+		t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+	}
+
+	// Reversed indices swap the columns, which leaves both answers unchanged
+	res = solveString(t, input, ParseOptions{FieldIndices: []int{3, 1}})
+	if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+		t.Errorf("reversed answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+	}
+
+	// A line too short for index 3 is skipped, or an error with Strict set
+	short := input + "g 5 t\n"
+	if res := solveString(t, short, ParseOptions{FieldIndices: []int{1, 3}}); res.LinesSkipped != 1 || res.SimilarityScore != 31 {
+		t.Errorf("short line: similarity %d with %d skipped, want 31 with 1", res.SimilarityScore, res.LinesSkipped)
+	}
+	_, err := Solve(strings.NewReader(short), ParseOptions{FieldIndices: []int{1, 3}, Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "line 7") || !strings.Contains(err.Error(), "expected at least 4 fields, got 3") {
+		t.Errorf("strict error = %v, want line 7's field count", err)
+	}
+}
+
+func TestLeadingZeros(t *testing.T) {
+	const input = "0042 42\n42 00042\n007 7\n"
+	for name, opts := range map[string]ParseOptions{"sequential": {}, "parallel": {Workers: 2}, "mmap": {Mmap: true}} {
+		t.Run(name, func(t *testing.T) {
+			cols, err := ParseColumns(writeInput(t, input), opts)
+			if err != nil {
+				t.Fatalf("ParseColumns: %v", err)
+			}
+			// Zero-padded values stay decimal and share a key with the unpadded ones
+			freq := Frequencies(cols.Right)
+			if freq[42] != 2 || freq[7] != 1 || len(freq) != 2 {
+				t.Errorf("Frequencies = %v, want map[7:1 42:2]", freq)
+			}
+			if got, want := SimilarityScore(cols.Left, cols.Right), int64(42*2+42*2+7); got != want {
+				t.Errorf("SimilarityScore = %d, want %d", got, want)
+			}
+		})
+	}
+	// 08 would be invalid octal, so a base-8 reading would have to skip it
+	if res := solveString(t, "08 8\n", ParseOptions{Strict: true}); res.SimilarityScore != 8 {
+		t.Errorf("similarity of 08 8 = %d, want 8", res.SimilarityScore)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	// The example with weights 1 through 6: 1·3·3 + 2·4·1 + 5·3·3 + 6·3·3
+	const input = "3 4 1\n4 3 2\n2 5 3\n1 3 4\n3 9 5\n3 3 6\n"
+	for name, opts := range map[string]ParseOptions{"sequential": {Weighted: true}, "parallel": {Weighted: true, Workers: 3}} {
+		t.Run(name, func(t *testing.T) {
+			res, err := SolveFile(writeInput(t, input), opts)
+			if err != nil {
+				t.Fatalf("SolveFile: %v", err)
+			}
+			if res.TotalDistance != 11 || res.SimilarityScore != 116 {
+				t.Errorf("answers = %d, %d, want 11, 116", res.TotalDistance, res.SimilarityScore)
+			}
+		})
+	}
+
+	// Unit weights give the plain score
+	ones := strings.ReplaceAll(strings.TrimSpace(example), "\n", " 1\n") + " 1\n"
+	if res := solveString(t, ones, ParseOptions{Weighted: true}); res.SimilarityScore != 31 {
+		t.Errorf("similarity with unit weights = %d, want 31", res.SimilarityScore)
+	}
+
+	// A missing weight fails the parse even when malformed lines are skipped
+	for _, strict := range []bool{false, true} {
+		_, err := Solve(strings.NewReader("3 4 1\n4 3\n"), ParseOptions{Weighted: true, Strict: strict})
+		if err == nil || !strings.Contains(err.Error(), "line 2") || !strings.Contains(err.Error(), "missing weight in the third field") {
+			t.Errorf("strict=%v: error = %v, want line 2's missing weight", strict, err)
+		}
+	}
+}
+
+func TestMaxValue(t *testing.T) {
+	const input = "3 4\n4 3\n2 5\n1 3\n3 9\n3 3\n5 100000\n"
+	opts := ParseOptions{MaxValue: 1000}
+
+	res := solveString(t, input, opts)
+	if res.LinesSkipped != 1 || res.TotalDistance != 11 || res.SimilarityScore != 31 {
+		t.Errorf("answers = %d, %d with %d skipped, want 11, 31 with 1", res.TotalDistance, res.SimilarityScore, res.LinesSkipped)
+	}
+	// The limit is inclusive
+	if res := solveString(t, "1000 1000\n", ParseOptions{MaxValue: 1000, Strict: true}); res.SimilarityScore != 1000 {
+		t.Errorf("similarity at the limit = %d, want 1000", res.SimilarityScore)
+	}
+
+	opts.Strict = true
+	_, err := Solve(strings.NewReader(input), opts)
+	if err == nil || !strings.Contains(err.Error(), "line 7") || !strings.Contains(err.Error(), "value 100000 exceeds the maximum 1000") {
+		t.Errorf("strict error = %v, want line 7's value reported", err)
+	}
+}
+
+func TestSingleColumnInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  ParseOptions
+		want  string
+	}{
+		{name: "one column", input: "3\n4\n\n2\n", want: "no two-column lines found — is this the right input format?"},
+		{name: "one column in parallel", input: "3\n4\n\n2\n", opts: ParseOptions{Workers: 2}, want: "no two-column lines found"},
+		{name: "weighted", input: "3 4 1 9\n", opts: ParseOptions{Weighted: true}, want: "no three-column lines found"},
+		{name: "field indices", input: "3 4\n", opts: ParseOptions{FieldIndices: []int{0, 2}}, want: "no lines with at least 3 fields found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SolveFile(writeInput(t, tt.input), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SolveFile error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// One good line among single values is enough to solve, and blank input is no error
+	if res := solveString(t, "3\n4\n1 1\n", ParseOptions{}); res.LinesSkipped != 2 || res.SimilarityScore != 1 {
+		t.Errorf("mixed input: similarity %d with %d skipped, want 1 with 2", res.SimilarityScore, res.LinesSkipped)
+	}
+	solveString(t, "\n\n", ParseOptions{})
+}
+
+func TestSignPrefixes(t *testing.T) {
+	res := solveString(t, "+3 4\n-4 +3\n+3 +3\n", ParseOptions{Strict: true})
+	// Left -4, 3, 3 against right 3, 3, 4
+	if res.TotalDistance != 7+0+1 || res.SimilarityScore != 3*2+3*2 {
+		t.Errorf("answers = %d, %d, want 8, 12", res.TotalDistance, res.SimilarityScore)
+	}
+	_, err := Solve(strings.NewReader("3 4\n++3 4\n"), ParseOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Solve error = %v, want a doubled sign rejected on line 2", err)
+	}
+}
+
+func TestSkippedLines(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("3 4\nbad line\n4 3\n\n")
+	for range 50 {
+		b.WriteString("x y\n")
+	}
+	input := b.String()
+
+	cols, err := ReadColumns(strings.NewReader(input), ParseOptions{SkipSamples: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols.Skipped != 52 {
+		t.Errorf("Skipped = %d, want 52", cols.Skipped)
+	}
+	// Blank lines are skipped without being sampled
+	want := []SkippedLine{{Line: 2, Text: "bad line"}, {Line: 5, Text: "x y"}, {Line: 6, Text: "x y"}}
+	if len(cols.SkippedLines) != len(want) {
+		t.Fatalf("SkippedLines = %v, want the first %d", cols.SkippedLines, len(want))
+	}
+	for i, got := range cols.SkippedLines {
+		if got.Line != want[i].Line || got.Text != want[i].Text || got.Err == nil {
+			t.Errorf("SkippedLines[%d] = %+v, want line %d %q with an error", i, got, want[i].Line, want[i].Text)
+		}
+	}
+
+	// Parallel chunks renumber their samples and share the cap
+	path := writeInput(t, input)
+	cols, err = ParseColumns(path, ParseOptions{SkipSamples: 3, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols.SkippedLines) != 3 || cols.SkippedLines[0].Line != 2 || cols.SkippedLines[2].Line != 6 {
+		t.Errorf("parallel SkippedLines = %v, want lines 2, 5 and 6", cols.SkippedLines)
+	}
+
+	cols, err = ReadColumns(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols.SkippedLines != nil {
+		t.Errorf("SkippedLines = %v with no samples requested, want none", cols.SkippedLines)
+	}
+}
+
+func TestBase(t *testing.T) {
+	// The example's values written in hex and octal give its decimal answers
+	tests := []struct {
+		name  string
+		input string
+		base  int
+	}{
+		{name: "hex", input: "0x3 0x4\n0x4 3\n2 5\n1 0x3\n3 9\n3 0X3\n", base: 16},
+		{name: "octal", input: "3 4\n4 3\n2 5\n1 3\n3 11\n3 3\n", base: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := solveString(t, tt.input, ParseOptions{Strict: true, Base: tt.base})
+			if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+				t.Errorf("answers = %d, %d, want 11, 31", res.TotalDistance, res.SimilarityScore)
+			}
+		})
+	}
+
+	// Hex values above 9 count in decimal: 0x1a is 26
+	res := solveString(t, "0x1a 1a\n0x1a 0x1a\n", ParseOptions{Strict: true, Base: 16})
+	if res.SimilarityScore != 26*2*2 {
+		t.Errorf("similarity = %d, want %d", res.SimilarityScore, 26*2*2)
+	}
+	if _, err := Solve(strings.NewReader("8 1\n"), ParseOptions{Strict: true, Base: 8}); err == nil {
+		t.Error("Solve accepted the digit 8 in octal input")
+	}
+}
+
+func TestSkipLine(t *testing.T) {
+	const input = "3 4\n// left and right IDs\n4 3\n2 5\r\n// 100 100\r\n1 3\n3 9\n3 3\n// end\n"
+	var seen []int
+	opts := ParseOptions{
+		Strict: true,
+		SkipLine: func(lineNum int, raw string) bool {
+			seen = append(seen, lineNum)
+			if strings.HasSuffix(raw, "\r") {
+				t.Errorf("line %d handed to the callback with its carriage return", lineNum)
+			}
+			return strings.HasPrefix(raw, "//")
+		},
+	}
+	res := solveString(t, input, opts)
+	if res.TotalDistance != 11 || res.SimilarityScore != 31 {
+		t.Errorf("answers = %d, %d, want the example's 11, 31", res.TotalDistance, res.SimilarityScore)
+	}
+	if res.LinesCommented != 3 || res.LinesParsed != 6 {
+		t.Errorf("%d lines skipped by the callback and %d parsed, want 3 and 6", res.LinesCommented, res.LinesParsed)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(seen, want) {
+		t.Errorf("callback saw lines %v, want %v", seen, want)
+	}
+
+	// Without the callback strict parsing rejects the first line
+	if _, err := Solve(strings.NewReader(input), ParseOptions{Strict: true}); err == nil {
+		t.Error("Solve accepted // lines without a SkipLine callback")
+	}
+}
+
+func TestPad(t *testing.T) {
+	// The bad right values leave the left column two longer
+	const input = "3 4\n4 3\n2 x\n5 y\n"
+	if _, err := Solve(strings.NewReader(input), ParseOptions{}); err == nil || !strings.Contains(err.Error(), "unbalanced") {
+		t.Fatalf("Solve without padding = %v, want an unbalanced columns error", err)
+	}
+
+	tests := []struct {
+		value                int64
+		distance, similarity int64
+	}{
+		// Left 2 3 4 5 against right 0 0 3 4
+		{value: 0, distance: 7, similarity: 3 + 4},
+		// Left 2 3 4 5 against right 2 2 3 4
+		{value: 2, distance: 3, similarity: 2*2 + 3 + 4},
+	}
+	for _, tt := range tests {
+		cols, err := ReadColumns(strings.NewReader(input), ParseOptions{Pad: true, PadValue: tt.value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cols.Padded != 2 || len(cols.Right) != 4 {
+			t.Errorf("pad %d: %d values padded to %d right values, want 2 to 4", tt.value, cols.Padded, len(cols.Right))
+		}
+		res := NewResult(cols, Frequencies(cols.Right))
+		if res.TotalDistance != tt.distance || res.SimilarityScore != tt.similarity {
+			t.Errorf("pad %d: answers = %d, %d, want %d, %d", tt.value, res.TotalDistance, res.SimilarityScore, tt.distance, tt.similarity)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		headers int
+		skipped int
+	}{
+		{name: "header", input: "left right\n" + example, headers: 1},
+		{name: "CRLF header", input: "Left\tRight\r\n" + example, headers: 1},
+		// Only the first line can be a header
+		{name: "later row", input: example + "left right\n", skipped: 1},
+		{name: "numeric field", input: "id 4 5\n" + example, skipped: 1},
+		{name: "one field", input: "ids\n" + example, skipped: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, err := ReadColumns(strings.NewReader(tt.input), ParseOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cols.Headers != tt.headers || cols.Skipped != tt.skipped {
+				t.Errorf("%d headers and %d skipped, want %d and %d", cols.Headers, cols.Skipped, tt.headers, tt.skipped)
+			}
+			if len(cols.Left) != 6 {
+				t.Errorf("%d pairs parsed, want the example's 6", len(cols.Left))
+			}
+		})
+	}
+
+	// A header is recognized even in strict mode
+	res := solveString(t, "left right\n"+example, ParseOptions{Strict: true})
+	if res.SimilarityScore != 31 || res.LinesParsed != 6 || res.LinesSkipped != 0 {
+		t.Errorf("strict solve = %d over %d lines, %d skipped, want 31 over 6, none skipped", res.SimilarityScore, res.LinesParsed, res.LinesSkipped)
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  LineContext
+	}{
+		{name: "bad left", input: "3 4\nx3   4\n", want: LineContext{Line: 2, Text: "x3   4", Column: 0}},
+		{name: "bad right", input: "3 4\n4 3\n4   3y\r\n", want: LineContext{Line: 3, Text: "4   3y", Column: 4}},
+		{name: "tab", input: "4\t-\n", want: LineContext{Line: 1, Text: "4\t-", Column: 2}},
+		{name: "extra field", input: "1 2 3\n", want: LineContext{Line: 1, Text: "1 2 3", Column: 4}},
+		{name: "missing field", input: "1 2\n7\n", want: LineContext{Line: 2, Text: "7", Column: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Solve(strings.NewReader(tt.input), ParseOptions{Strict: true})
+			if err == nil {
+				t.Fatal("Solve succeeded on a malformed line")
+			}
+			got, ok := ErrorContext(err)
+			if !ok || got != tt.want {
+				t.Errorf("ErrorContext = %+v, %v, want %+v", got, ok, tt.want)
+			}
+			if want := fmt.Sprintf("line %d", tt.want.Line); !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not name %s", err, want)
+			}
+		})
+	}
+
+	if _, ok := ErrorContext(errors.New("not a parse error")); ok {
+		t.Error("ErrorContext found a line in an unrelated error")
+	}
+}
+
+func TestSince(t *testing.T) {
+	const input = "3 4 100\n4 3 200\n3 3 250\n1 3 300\n"
+	// Only the last three lines are stamped at or after 200
+	res := solveString(t, input, ParseOptions{Timestamped: true, Since: 200})
+	if res.TotalDistance != 3 || res.SimilarityScore != 9 || res.LinesParsed != 3 {
+		t.Errorf("answers = %d, %d over %d lines, want 3, 9 over 3", res.TotalDistance, res.SimilarityScore, res.LinesParsed)
+	}
+	res = solveString(t, input, ParseOptions{Timestamped: true})
+	if res.TotalDistance != 2 || res.SimilarityScore != 22 {
+		t.Errorf("unfiltered answers = %d, %d, want 2, 22", res.TotalDistance, res.SimilarityScore)
+	}
+
+	// Two-column input has no timestamps to filter on
+	_, err := Solve(strings.NewReader(example), ParseOptions{Timestamped: true, Since: 200})
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Solve without timestamps = %v, want an error on line 1", err)
+	}
+}
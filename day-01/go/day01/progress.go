@@ -0,0 +1,45 @@
+package day01
+
+import "sync/atomic"
+
+// Progress counts what a parse has read so far. It is updated in batches while
+// parsing and is safe to read from another goroutine at any time; the counts are
+// exact once parsing returns.
+type Progress struct {
+	lines atomic.Int64
+	bytes atomic.Int64
+}
+
+// Lines returns the number of lines read so far.
+func (p *Progress) Lines() int64 {
+	return p.lines.Load()
+}
+
+// Bytes returns the number of input bytes consumed so far, counting one byte per
+// line terminator. For compressed input it counts decompressed bytes.
+func (p *Progress) Bytes() int64 {
+	return p.bytes.Load()
+}
+
+// progressTracker accumulates counts locally so the shared Progress is touched
+// only once per batch of lines. A nil progress makes it a no-op.
+type progressTracker struct {
+	progress     *Progress
+	lines, bytes int64
+}
+
+// line records one line of n bytes, not counting its terminator.
+func (t *progressTracker) line(n int) {
+	t.lines++
+	t.bytes += int64(n) + 1
+}
+
+// flush publishes the pending counts.
+func (t *progressTracker) flush() {
+	if t.progress == nil || t.lines == 0 {
+		return
+	}
+	t.progress.lines.Add(t.lines)
+	t.progress.bytes.Add(t.bytes)
+	t.lines, t.bytes = 0, 0
+}
@@ -0,0 +1,39 @@
+package day01
+
+import (
+	"io"
+	"strconv"
+
+	"aoc-2024/internal/registry"
+)
+
+func init() {
+	registry.Register(day{})
+}
+
+// day adapts Part1 and Part2 to registry.Day.
+type day struct{}
+
+func (day) Number() int { return 1 }
+
+func (day) Part1(r io.Reader) (string, error) { return Part1(r) }
+
+func (day) Part2(r io.Reader) (string, error) { return Part2(r) }
+
+// Part1 returns the total distance between the two lists read from r.
+func Part1(r io.Reader) (string, error) {
+	cols, err := ReadColumns(r, ParseOptions{})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(TotalDistance(cols.Left, cols.Right), 10), nil
+}
+
+// Part2 returns the similarity score between the two lists read from r.
+func Part2(r io.Reader) (string, error) {
+	cols, err := ReadColumns(r, ParseOptions{})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(SimilarityScore(cols.Left, cols.Right), 10), nil
+}
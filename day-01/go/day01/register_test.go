@@ -0,0 +1,24 @@
+package day01
+
+import (
+	"strings"
+	"testing"
+
+	"aoc-2024/internal/registry"
+)
+
+func TestRegistered(t *testing.T) {
+	for part, want := range map[int]string{1: "11", 2: "31"} {
+		solve, ok := registry.Lookup(1, part)
+		if !ok {
+			t.Fatalf("no solver registered for day 1 part %d", part)
+		}
+		got, err := solve(strings.NewReader(example))
+		if err != nil {
+			t.Fatalf("day 1 part %d: %v", part, err)
+		}
+		if got != want {
+			t.Errorf("day 1 part %d = %q, want %q", part, got, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package day01
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result holds both answers along with parse accounting and timing.
+// Durations are encoded in JSON as integer nanoseconds.
+type Result struct {
+	SimilarityScore int64 `json:"similarity_score"`
+	TotalDistance   int64 `json:"total_distance"`
+	// LinesParsed is the number of lines that contributed a pair to both columns.
+	LinesParsed int `json:"lines_parsed"`
+	// LinesSkipped is the number of malformed lines left out of the answers.
+	LinesSkipped int `json:"lines_skipped"`
+	// LinesCommented is the number of comment lines ignored.
+	LinesCommented int `json:"lines_commented"`
+	// ParseElapsed and CalcElapsed split Elapsed into reading the input and
+	// computing the answers.
+	ParseElapsed time.Duration `json:"parse_elapsed_ns"`
+	CalcElapsed  time.Duration `json:"calc_elapsed_ns"`
+	Elapsed      time.Duration `json:"elapsed_ns"`
+}
+
+// String returns both answers in the labelled two-line form the CLI prints,
+// without a trailing newline.
+func (r *Result) String() string {
+	return fmt.Sprintf("Part 1 (total distance): %d\nPart 2 (similarity score): %d",
+		r.TotalDistance, r.SimilarityScore)
+}
+
+// WriteJSON writes r to w as a single JSON object followed by a newline.
+func (r *Result) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// resultJSON is Result without its methods, so encoding/json handles it field by
+// field using the struct tags.
+type resultJSON Result
+
+// MarshalJSON implements json.Marshaler. Without it encoding/json would prefer
+// MarshalText and write the text form as a single JSON string.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(resultJSON(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the object MarshalJSON writes.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*resultJSON)(r))
+}
+
+// MarshalText implements encoding.TextMarshaler with one key=value line per field,
+// sorted by key. The answers use the keys of the CLI's -format kv output, and
+// durations are integer nanoseconds under the same keys as in JSON.
+func (r Result) MarshalText() ([]byte, error) {
+	var text []byte
+	for _, f := range r.textFields() {
+		text = append(text, f.key...)
+		text = append(text, '=')
+		text = strconv.AppendInt(text, f.value, 10)
+		text = append(text, '\n')
+	}
+	return text, nil
+}
+
+// textField is one key=value line of the text form of a Result.
+type textField struct {
+	key   string
+	value int64
+}
+
+// textFields returns the fields of the text form, sorted by key.
+func (r Result) textFields() []textField {
+	return []textField{
+		{"calc_elapsed_ns", int64(r.CalcElapsed)},
+		{"distance", r.TotalDistance},
+		{"elapsed_ns", int64(r.Elapsed)},
+		{"lines_commented", int64(r.LinesCommented)},
+		{"lines_parsed", int64(r.LinesParsed)},
+		{"lines_skipped", int64(r.LinesSkipped)},
+		{"parse_elapsed_ns", int64(r.ParseElapsed)},
+		{"similarity", r.SimilarityScore},
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the form written by
+// MarshalText in any order. Missing keys leave their fields zero; unknown keys
+// are an error.
+func (r *Result) UnmarshalText(text []byte) error {
+	var res Result
+	lines := strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+	if len(text) == 0 {
+		lines = nil
+	}
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("invalid result line %d: %q", i+1, line)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid result value for %s: %q", key, value)
+		}
+		switch key {
+		case "calc_elapsed_ns":
+			res.CalcElapsed = time.Duration(n)
+		case "distance":
+			res.TotalDistance = n
+		case "elapsed_ns":
+			res.Elapsed = time.Duration(n)
+		case "lines_commented":
+			res.LinesCommented = int(n)
+		case "lines_parsed":
+			res.LinesParsed = int(n)
+		case "lines_skipped":
+			res.LinesSkipped = int(n)
+		case "parse_elapsed_ns":
+			res.ParseElapsed = time.Duration(n)
+		case "similarity":
+			res.SimilarityScore = n
+		default:
+			return fmt.Errorf("unknown result key %q", key)
+		}
+	}
+	*r = res
+	return nil
+}
+
+// NewResult computes both answers from parsed columns and the frequency map of the
+// right column. The columns are sorted in place by the distance calculation. When
+// cols carries Weights the similarity score is the weighted one.
+func NewResult(cols *Columns, rightFreq map[int64]int64) *Result {
+	// Weights line up with the left column as parsed, so score before sorting
+	similarity := cols.score(rightFreq)
+	SortColumns(cols.Left, cols.Right)
+	return cols.result(sortedDistance(cols.Left, cols.Right), similarity)
+}
+
+// score computes the similarity score of c, weighted when c carries Weights.
+func (c *Columns) score(rightFreq map[int64]int64) int64 {
+	if c.Weights != nil {
+		return weightedScore(c.Left, c.Weights, rightFreq)
+	}
+	return ScoreWithFrequencies(c.Left, rightFreq)
+}
+
+// result wraps answers computed from c with its line accounting.
+func (c *Columns) result(distance, similarity int64) *Result {
+	return &Result{
+		SimilarityScore: similarity,
+		TotalDistance:   distance,
+		LinesParsed:     c.Lines - c.Skipped - c.Comments - c.Headers,
+		LinesSkipped:    c.Skipped,
+		LinesCommented:  c.Comments,
+	}
+}
+
+// Solve parses r and computes both answers. When parsing fails partway the
+// error comes with a partial Result over the lines parsed before the failure.
+func Solve(r io.Reader, opts ParseOptions) (*Result, error) {
+	start := time.Now()
+	cols, err := ReadColumns(r, opts)
+	if err != nil {
+		return partialResult(cols, start), err
+	}
+	return finish(cols, start, Frequencies), nil
+}
+
+// SolveFile parses filename, or stdin when filename is "-", and computes both
+// answers. Errors come with a partial Result as for Solve, or a nil one if the
+// input could not be opened.
+func SolveFile(filename string, opts ParseOptions) (*Result, error) {
+	start := time.Now()
+	cols, err := ParseColumns(filename, opts)
+	if err != nil {
+		return partialResult(cols, start), err
+	}
+	return finish(cols, start, Frequencies), nil
+}
+
+// partialResult computes the answers over the columns parsed before an error, for
+// diagnostics. It returns nil when nothing was read, and only the line accounting
+// when the lines parsed so far do not pair up.
+func partialResult(cols *Columns, start time.Time) *Result {
+	if cols == nil {
+		return nil
+	}
+	if len(cols.Left) != len(cols.Right) || (cols.Weights != nil && len(cols.Weights) != len(cols.Left)) {
+		res := cols.result(0, 0)
+		res.Elapsed = time.Since(start)
+		return res
+	}
+	return finish(cols, start, Frequencies)
+}
+
+// finish computes the answers for cols with a single sort and stamps the time
+// elapsed since start, treating everything before the call as parsing.
+// frequencies counts the right column and is only called for weighted columns.
+func finish(cols *Columns, start time.Time, frequencies func(right []int64) map[int64]int64) *Result {
+	calcStart := time.Now()
+	var res *Result
+	if cols.Weights != nil {
+		// Scoring by run lengths after the sort would lose each value's weight
+		res = NewResult(cols, frequencies(cols.Right))
+	} else {
+		res = cols.result(SolveBoth(cols.Left, cols.Right))
+	}
+	res.ParseElapsed = calcStart.Sub(start)
+	res.CalcElapsed = time.Since(calcStart)
+	res.Elapsed = time.Since(start)
+	return res
+}
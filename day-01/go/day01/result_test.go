@@ -0,0 +1,98 @@
+package day01
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultDurations(t *testing.T) {
+	res, err := Solve(bytes.NewReader(benchInput()), ParseOptions{})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if res.ParseElapsed < 0 || res.CalcElapsed < 0 {
+		t.Fatalf("negative durations: parse %v, calc %v", res.ParseElapsed, res.CalcElapsed)
+	}
+	// The phases cover the whole solve, bar the instant between stamping them
+	sum := res.ParseElapsed + res.CalcElapsed
+	if sum > res.Elapsed || res.Elapsed-sum > time.Millisecond {
+		t.Errorf("parse %v + calc %v = %v, want roughly the elapsed %v", res.ParseElapsed, res.CalcElapsed, sum, res.Elapsed)
+	}
+}
+
+// sampleResult has a distinct value in every field, so a field lost or swapped in
+// a round trip shows up.
+var sampleResult = Result{
+	SimilarityScore: 31,
+	TotalDistance:   11,
+	LinesParsed:     6,
+	LinesSkipped:    2,
+	LinesCommented:  1,
+	ParseElapsed:    3 * time.Millisecond,
+	CalcElapsed:     4 * time.Microsecond,
+	Elapsed:         5 * time.Second,
+}
+
+func TestResultString(t *testing.T) {
+	want := "Part 1 (total distance): 11\nPart 2 (similarity score): 31"
+	if got := sampleResult.String(); got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleResult.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "}\n") || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("WriteJSON wrote %q, want one line", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"elapsed_ns":5000000000`) {
+		t.Errorf("WriteJSON wrote %q, want durations in integer nanoseconds", buf.String())
+	}
+
+	var got Result
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got != sampleResult {
+		t.Errorf("round trip = %+v, want %+v", got, sampleResult)
+	}
+}
+
+func TestResultText(t *testing.T) {
+	text, err := sampleResult.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if !strings.HasPrefix(string(text), "calc_elapsed_ns=4000\ndistance=11\n") || !strings.HasSuffix(string(text), "similarity=31\n") {
+		t.Errorf("MarshalText = %q, want sorted key=value lines", text)
+	}
+
+	var got Result
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if got != sampleResult {
+		t.Errorf("round trip = %+v, want %+v", got, sampleResult)
+	}
+
+	// Empty text is a zero Result
+	var empty Result
+	if err := empty.UnmarshalText(nil); err != nil || empty != (Result{}) {
+		t.Errorf("UnmarshalText(nil) = %+v, %v, want a zero Result", empty, err)
+	}
+
+	for _, bad := range []string{"distance", "distance=x", "answer=42"} {
+		if err := got.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded, want an error", bad)
+		}
+	}
+	if got != sampleResult {
+		t.Error("a failed UnmarshalText modified the Result")
+	}
+}
@@ -0,0 +1,51 @@
+package day01
+
+// StreamingScorer computes the similarity score while pairs are fed in one line at
+// a time, for tools that show a running total.
+//
+// The score is Σ x·L(x)·R(x), where L and R count each value in the left and right
+// lists, so both frequency maps are kept and each Feed adds exactly what the new
+// values contribute. Current is therefore the exact score of the lines fed so far;
+// it only equals the answer for the whole input once every line has been fed,
+// which Finalize marks.
+type StreamingScorer struct {
+	leftFreq  map[int64]int64
+	rightFreq map[int64]int64
+	score     int64
+	final     bool
+}
+
+// NewStreamingScorer returns an empty StreamingScorer.
+func NewStreamingScorer() *StreamingScorer {
+	return &StreamingScorer{
+		leftFreq:  make(map[int64]int64),
+		rightFreq: make(map[int64]int64),
+	}
+}
+
+// Feed adds one input line. It panics if called after Finalize.
+// Time Complexity: O(1) expected
+func (s *StreamingScorer) Feed(left, right int64) {
+	if s.final {
+		panic("day01: StreamingScorer.Feed called after Finalize")
+	}
+
+	// The new left value matches every right value seen so far, and the new right
+	// value every left value, including the one just added
+	s.score += left * s.rightFreq[left]
+	s.leftFreq[left]++
+	s.score += right * s.leftFreq[right]
+	s.rightFreq[right]++
+}
+
+// Current returns the similarity score of the lines fed so far.
+func (s *StreamingScorer) Current() int64 {
+	return s.score
+}
+
+// Finalize marks the input as complete and returns the similarity score, which
+// equals SimilarityScore over every fed line.
+func (s *StreamingScorer) Finalize() int64 {
+	s.final = true
+	return s.score
+}
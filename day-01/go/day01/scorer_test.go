@@ -0,0 +1,34 @@
+package day01
+
+import "testing"
+
+func TestStreamingScorer(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		left, right := randomColumns(seed, 200, 10)
+		s := NewStreamingScorer()
+		for i := range left {
+			s.Feed(left[i], right[i])
+			// Every running total is exact for the prefix fed so far
+			if i%50 == 0 {
+				if got, want := s.Current(), SimilarityScore(left[:i+1], right[:i+1]); got != want {
+					t.Fatalf("seed %d: Current after %d lines = %d, want %d", seed, i+1, got, want)
+				}
+			}
+		}
+		if got, want := s.Finalize(), SimilarityScore(left, right); got != want {
+			t.Errorf("seed %d: Finalize = %d, SimilarityScore = %d", seed, got, want)
+		}
+	}
+}
+
+func TestStreamingScorerFeedAfterFinalize(t *testing.T) {
+	s := NewStreamingScorer()
+	s.Feed(3, 3)
+	s.Finalize()
+	defer func() {
+		if recover() == nil {
+			t.Error("Feed after Finalize did not panic")
+		}
+	}()
+	s.Feed(1, 1)
+}
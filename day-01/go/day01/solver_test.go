@@ -0,0 +1,106 @@
+package day01
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSolverReuse(t *testing.T) {
+	s := NewSolver(ParseOptions{})
+	// A smaller input after a larger one must not see the earlier values
+	for _, tt := range []struct {
+		input                string
+		distance, similarity int64
+	}{
+		{example, 11, 31},
+		{"1 1\n", 0, 1},
+		{example + example, 22, 124},
+	} {
+		res, err := s.Solve(strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+		if res.TotalDistance != tt.distance || res.SimilarityScore != tt.similarity {
+			t.Errorf("answers = %d, %d, want %d, %d", res.TotalDistance, res.SimilarityScore, tt.distance, tt.similarity)
+		}
+	}
+}
+
+func TestSolverMatchesSolve(t *testing.T) {
+	var weighted strings.Builder
+	for i, line := range strings.Split(strings.TrimSpace(example), "\n") {
+		fmt.Fprintf(&weighted, "%s %d\n", line, i%3+1)
+	}
+	for _, tt := range []struct {
+		input string
+		opts  ParseOptions
+	}{
+		{example, ParseOptions{}},
+		{weighted.String(), ParseOptions{Weighted: true}},
+	} {
+		want := solveString(t, tt.input, tt.opts)
+		s := NewSolver(tt.opts)
+		// The second run goes through the cleared buffers of the first
+		for run := 1; run <= 2; run++ {
+			res, err := s.Solve(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Solve: %v", err)
+			}
+			if res.TotalDistance != want.TotalDistance || res.SimilarityScore != want.SimilarityScore {
+				t.Errorf("%+v run %d: answers = %d, %d, want %d, %d", tt.opts, run, res.TotalDistance, res.SimilarityScore, want.TotalDistance, want.SimilarityScore)
+			}
+		}
+	}
+}
+
+func TestSolverCache(t *testing.T) {
+	path := writeInput(t, example)
+	s := &Solver{Cache: true}
+	solve := func(distance, similarity int64) {
+		t.Helper()
+		res, err := s.SolveFile(path)
+		if err != nil {
+			t.Fatalf("SolveFile: %v", err)
+		}
+		if res.TotalDistance != distance || res.SimilarityScore != similarity {
+			t.Errorf("answers = %d, %d, want %d, %d", res.TotalDistance, res.SimilarityScore, distance, similarity)
+		}
+	}
+	// rewrite replaces the file's content and sets its modification time.
+	rewrite := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	rewrite(example, stamp)
+
+	solve(11, 31)
+	first := s.cached
+	solve(11, 31)
+	if s.cached != first {
+		t.Error("re-solving an unchanged file replaced the cache")
+	}
+
+	// Same size and time: only a hit can still give the old answers
+	rewrite(strings.Replace(example, "3   3", "3   4", 1), stamp)
+	solve(11, 31)
+
+	// A new modification time refreshes the cache, even at the same size
+	rewrite(strings.Replace(example, "3   3", "3   4", 1), stamp.Add(time.Second))
+	solve(12, 26)
+	if s.cached == first {
+		t.Error("a changed modification time did not refresh the cache")
+	}
+
+	// So does a new size
+	rewrite(example+"5   5\n", stamp.Add(time.Second))
+	solve(11, 41)
+}
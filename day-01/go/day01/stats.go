@@ -0,0 +1,62 @@
+package day01
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Stats summarizes the frequency distribution behind the similarity score.
+type Stats struct {
+	// UniqueRight is the number of distinct values in the right list.
+	UniqueRight int
+	// MostFrequent is the right value with the highest count, the smallest on ties.
+	MostFrequent      int64
+	MostFrequentCount int64
+	// UnmatchedLeft is the number of left values that never appear in the right list.
+	UnmatchedLeft int
+}
+
+// ComputeStats derives Stats from the left list and the right frequency map.
+// Time Complexity: O(n + m) where n is length of left list, m is unique right values
+func ComputeStats(left []int64, rightFreq map[int64]int64) Stats {
+	stats := Stats{UniqueRight: len(rightFreq)}
+	for value, count := range rightFreq {
+		if count > stats.MostFrequentCount ||
+			(count == stats.MostFrequentCount && value < stats.MostFrequent) {
+			stats.MostFrequent = value
+			stats.MostFrequentCount = count
+		}
+	}
+
+	for _, leftNum := range left {
+		if rightFreq[leftNum] == 0 {
+			stats.UnmatchedLeft++
+		}
+	}
+	return stats
+}
+
+// HistogramBucket counts the distinct right values that occur exactly Frequency times.
+type HistogramBucket struct {
+	Frequency int64
+	Values    int
+}
+
+// FrequencyHistogram groups the right values by how often they occur, in ascending
+// order of frequency. The Values of all buckets sum to len(rightFreq).
+// Time Complexity: O(m + k log k) where m is unique right values, k is distinct frequencies
+func FrequencyHistogram(rightFreq map[int64]int64) []HistogramBucket {
+	counts := make(map[int64]int)
+	for _, count := range rightFreq {
+		counts[count]++
+	}
+
+	buckets := make([]HistogramBucket, 0, len(counts))
+	for frequency, values := range counts {
+		buckets = append(buckets, HistogramBucket{Frequency: frequency, Values: values})
+	}
+	slices.SortFunc(buckets, func(a, b HistogramBucket) int {
+		return cmp.Compare(a.Frequency, b.Frequency)
+	})
+	return buckets
+}
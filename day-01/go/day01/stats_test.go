@@ -0,0 +1,41 @@
+package day01
+
+import (
+	"slices"
+	"testing"
+
+	"aoc-2024/internal/testutil"
+)
+
+func TestComputeStats(t *testing.T) {
+	left, right := testutil.MustParseColumns(t, example)
+	got := ComputeStats(left, Frequencies(right))
+	want := Stats{UniqueRight: 4, MostFrequent: 3, MostFrequentCount: 3, UnmatchedLeft: 2}
+	if got != want {
+		t.Errorf("ComputeStats = %+v, want %+v", got, want)
+	}
+}
+
+func TestFrequencyHistogram(t *testing.T) {
+	_, right := testutil.MustParseColumns(t, example)
+	want := []HistogramBucket{{Frequency: 1, Values: 3}, {Frequency: 3, Values: 1}}
+	if got := FrequencyHistogram(Frequencies(right)); !slices.Equal(got, want) {
+		t.Errorf("FrequencyHistogram = %v, want %v", got, want)
+	}
+
+	for seed := int64(1); seed <= 3; seed++ {
+		_, right := randomColumns(seed, 1000, 50)
+		rightFreq := Frequencies(right)
+		buckets := FrequencyHistogram(rightFreq)
+		values := 0
+		for i, b := range buckets {
+			values += b.Values
+			if i > 0 && b.Frequency <= buckets[i-1].Frequency {
+				t.Errorf("seed %d: bucket %d frequency %d does not follow %d", seed, i, b.Frequency, buckets[i-1].Frequency)
+			}
+		}
+		if values != len(rightFreq) {
+			t.Errorf("seed %d: buckets count %d values, want %d unique right values", seed, values, len(rightFreq))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package day01
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"aoc-2024/internal/input"
+)
+
+// StreamTotalDistance computes the total distance from two inputs that each hold
+// one integer per line, walking them in lockstep without materializing either list.
+//
+// Both inputs must already be sorted ascending; the precondition is verified while
+// streaming and a violation is reported as an error, as is a length mismatch.
+// Time Complexity: O(n)
+// Space Complexity: O(1) beyond the scanner buffers
+func StreamTotalDistance(left, right io.Reader) (int64, error) {
+	leftValues := newValueStream("left", left, true)
+	rightValues := newValueStream("right", right, true)
+
+	var total int64
+	for {
+		leftNum, leftOK, err := leftValues.next()
+		if err != nil {
+			return 0, err
+		}
+		rightNum, rightOK, err := rightValues.next()
+		if err != nil {
+			return 0, err
+		}
+
+		if leftOK != rightOK {
+			return 0, &unbalancedError{left: leftValues.total(), right: rightValues.total()}
+		}
+		if !leftOK {
+			return total, nil
+		}
+
+		diff := leftNum - rightNum
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+	}
+}
+
+// valueStream yields the integers of a one-value-per-line input, skipping blank
+// lines and, when ascending is set, checking they arrive in ascending order.
+type valueStream struct {
+	name      string
+	scanner   *bufio.Scanner
+	ascending bool
+	line      int
+	count     int
+	prev      int64
+	// descends records whether a value was ever smaller than the one before it.
+	descends bool
+}
+
+func newValueStream(name string, r io.Reader, ascending bool) *valueStream {
+	return &valueStream{name: name, scanner: input.NewScanner(r), ascending: ascending}
+}
+
+// next returns the next value, or false once the input is exhausted.
+func (s *valueStream) next() (int64, bool, error) {
+	for s.scanner.Scan() {
+		s.line++
+		text := s.scanner.Bytes()
+		if s.line == 1 {
+			text = input.TrimBOM(text)
+		}
+		field := bytes.TrimSpace(text)
+		if len(field) == 0 {
+			continue
+		}
+
+		value, ok := input.ParseInt(field)
+		if !ok {
+			return 0, false, fmt.Errorf("%s input: %w", s.name, &lineError{line: s.line, err: fmt.Errorf("invalid number: %q", field)})
+		}
+		if s.count > 0 && value < s.prev {
+			if s.ascending {
+				return 0, false, fmt.Errorf("%s input: %w", s.name, &lineError{line: s.line, err: errors.New("not sorted ascending")})
+			}
+			s.descends = true
+		}
+		s.prev = value
+		s.count++
+		return value, true, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return 0, false, fmt.Errorf("%s input: %v", s.name, input.ScanError(err, s.line+1))
+	}
+	return 0, false, nil
+}
+
+// total drains the stream and returns how many values it held, used only to
+// report accurate counts on a length mismatch.
+func (s *valueStream) total() int {
+	for {
+		_, ok, err := s.next()
+		if err != nil || !ok {
+			return s.count
+		}
+	}
+}
+
+// ReadSeparateColumns reads the left and right lists from two inputs that each hold
+// one integer per line, for puzzle variants that ship the lists as separate files.
+// Blank lines are ignored; any other malformed line is an error naming its input
+// and line, as are lists of different lengths.
+// Time Complexity: O(n)
+// Space Complexity: O(n) for both columns
+func ReadSeparateColumns(left, right io.Reader) (*Columns, error) {
+	leftStream := newValueStream("left", left, false)
+	leftValues, err := leftStream.all(capacityHint(sizeOf(left)))
+	if err != nil {
+		return nil, err
+	}
+	rightStream := newValueStream("right", right, false)
+	rightValues, err := rightStream.all(capacityHint(sizeOf(right)))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBalanced(leftValues, rightValues); err != nil {
+		return nil, err
+	}
+
+	// Each position stands in for one line of the two-column format
+	return &Columns{
+		Left:          leftValues,
+		Right:         rightValues,
+		Lines:         len(leftValues),
+		leftDescends:  leftStream.descends,
+		rightDescends: rightStream.descends,
+	}, nil
+}
+
+// ParseSeparateColumns is ReadSeparateColumns for two named inputs, with the same
+// handling of "-" and gzip as ParseColumns. Only opts.Gzip applies.
+func ParseSeparateColumns(leftFile, rightFile string, opts ParseOptions) (*Columns, error) {
+	left, err := openSource(leftFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer left.Close()
+
+	right, err := openSource(rightFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer right.Close()
+	return ReadSeparateColumns(left, right)
+}
+
+// all reads every remaining value, preallocating room for hint of them.
+func (s *valueStream) all(hint int) ([]int64, error) {
+	values := make([]int64, 0, hint)
+	for {
+		value, ok, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		values = append(values, value)
+	}
+}
@@ -0,0 +1,77 @@
+package day01
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// The example's columns, each sorted ascending, one value per line.
+const (
+	sortedLeft  = "1\n2\n3\n3\n3\n4\n"
+	sortedRight = "3\n3\n3\n4\n5\n9\n"
+)
+
+func TestStreamTotalDistance(t *testing.T) {
+	got, err := StreamTotalDistance(strings.NewReader(sortedLeft), strings.NewReader(sortedRight))
+	if err != nil {
+		t.Fatalf("StreamTotalDistance: %v", err)
+	}
+	if got != 11 {
+		t.Errorf("StreamTotalDistance = %d, want 11", got)
+	}
+}
+
+func TestStreamTotalDistanceErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right string
+		want        string
+	}{
+		{name: "unsorted left", left: "1\n3\n2\n", right: "1\n2\n3\n", want: "left input: line 3: not sorted ascending"},
+		{name: "unsorted right", left: "1\n2\n3\n", right: "5\n4\n6\n", want: "right input: line 2: not sorted ascending"},
+		{name: "shorter right", left: sortedLeft, right: "3\n3\n", want: "6 left values, 2 right values"},
+		{name: "invalid value", left: "1\nx\n", right: "1\n2\n", want: "left input: line 2: invalid number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StreamTotalDistance(strings.NewReader(tt.left), strings.NewReader(tt.right))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("StreamTotalDistance error = %v, want one containing %q", err, tt.want)
+			}
+			if !errors.Is(err, ErrParse) {
+				t.Errorf("error %v does not match ErrParse", err)
+			}
+		})
+	}
+}
+
+func TestSeparateColumns(t *testing.T) {
+	// The example's columns in input order, blank lines ignored
+	left := writeInput(t, "3\n4\n2\n\n1\n3\n3\n")
+	right := writeInput(t, "4\n3\n5\n3\n9\n3\n\n")
+	cols, err := ParseSeparateColumns(left, right, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseSeparateColumns: %v", err)
+	}
+	res := NewResult(cols, Frequencies(cols.Right))
+	want := solveString(t, example, ParseOptions{})
+	if res.TotalDistance != want.TotalDistance || res.SimilarityScore != want.SimilarityScore {
+		t.Errorf("answers = %d, %d, want %d, %d as for the two-column example", res.TotalDistance, res.SimilarityScore, want.TotalDistance, want.SimilarityScore)
+	}
+
+	tests := []struct {
+		name, left, right, want string
+	}{
+		{name: "different lengths", left: "1\n2\n3\n", right: "1\n2\n", want: "3 left values, 2 right values"},
+		{name: "malformed line", left: "1\n2\n", right: "1\n2 3\n", want: "right input: line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSeparateColumns(strings.NewReader(tt.left), strings.NewReader(tt.right))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadSeparateColumns error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package day01
+
+import "fmt"
+
+// Window is the similarity score of one run of consecutive pairs.
+type Window struct {
+	// Start and End are the zero-based, half-open range of pair indices covered.
+	Start, End int
+	Score      int64
+}
+
+// WindowScores computes the similarity score of every window of size consecutive
+// pairs in input order, starting a new window every step pairs. A trailing run
+// shorter than size is not scored.
+//
+// With global set each left value is weighed by its frequency in the whole right
+// list, so the windows partition the full score when step equals size. Otherwise
+// only the right values inside the same window are counted.
+// Time Complexity: O(n + w·size) for w windows
+// Space Complexity: O(m) for the frequency map
+func WindowScores(left, right []int64, size, step int, global bool) ([]Window, error) {
+	if size <= 0 || step <= 0 {
+		return nil, fmt.Errorf("window size and step must be positive, got %d and %d", size, step)
+	}
+	if err := checkBalanced(left, right); err != nil {
+		return nil, err
+	}
+
+	var rightFreq map[int64]int64
+	if global {
+		rightFreq = Frequencies(right)
+	} else {
+		rightFreq = make(map[int64]int64, size)
+	}
+
+	var windows []Window
+	for start := 0; start+size <= len(left); start += step {
+		end := start + size
+		if !global {
+			// Reuse the map across windows rather than allocating one per window
+			clear(rightFreq)
+			countInto(rightFreq, right[start:end])
+		}
+		windows = append(windows, Window{
+			Start: start,
+			End:   end,
+			Score: ScoreWithFrequencies(left[start:end], rightFreq),
+		})
+	}
+	return windows, nil
+}
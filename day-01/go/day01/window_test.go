@@ -0,0 +1,40 @@
+package day01
+
+import (
+	"slices"
+	"testing"
+
+	"aoc-2024/internal/testutil"
+)
+
+func TestWindowScores(t *testing.T) {
+	left, right := testutil.MustParseColumns(t, example)
+	tests := []struct {
+		name       string
+		size, step int
+		global     bool
+		want       []Window
+	}{
+		// Global windows that tile the input add up to the full score of 31
+		{name: "global tiling", size: 2, step: 2, global: true, want: []Window{{0, 2, 13}, {2, 4, 0}, {4, 6, 18}}},
+		{name: "local tiling", size: 2, step: 2, want: []Window{{0, 2, 7}, {2, 4, 0}, {4, 6, 6}}},
+		{name: "global overlapping", size: 3, step: 2, global: true, want: []Window{{0, 3, 13}, {2, 5, 9}}},
+		{name: "local overlapping", size: 3, step: 2, want: []Window{{0, 3, 7}, {2, 5, 3}}},
+		{name: "larger than the input", size: 7, step: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WindowScores(left, right, tt.size, tt.step, tt.global)
+			if err != nil {
+				t.Fatalf("WindowScores: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WindowScores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := WindowScores(left, right, 2, 0, false); err == nil {
+		t.Error("WindowScores accepted a step of 0")
+	}
+}
@@ -1,3 +0,0 @@
-module aoc-2024-day-01
-
-go 1.22.1
@@ -2,95 +2,1796 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"aoc-2024/day-01/go/day01"
 )
 
-// calculateSimilarityScore computes the similarity score between two lists of numbers.
-// Time Complexity: O(n + m) where n is length of left list, m is length of right list
-// Space Complexity: O(m) for the frequency map
-func calculateSimilarityScore(filename string) (int64, error) {
-	start := time.Now()
+// Exit codes distinguish the broad categories of failure for scripts:
+//
+//	0    success
+//	1    internal error, or anything not listed below
+//	2    input file not found
+//	3    malformed input
+//	4    answers differ from -expect, -expect-distance or -compare
+//	64   invalid flag or combination of flags
+//	130  interrupted by Ctrl-C
+const (
+	exitInternal     = 1
+	exitFileNotFound = 2
+	exitParse        = 3
+	exitMismatch     = 4
+	// exitUsage is EX_USAGE from sysexits.h, clear of the file-not-found code.
+	exitUsage = 64
+	// exitInterrupted follows the shell convention of 128 plus the signal number.
+	exitInterrupted = 130
+)
+
+// exitCode maps an error to the exit code for its category.
+func exitCode(err error) int {
+	var usage *usageError
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, day01.ErrFileNotFound):
+		return exitFileNotFound
+	case errors.Is(err, day01.ErrParse):
+		return exitParse
+	}
+	return exitInternal
+}
 
-	// Open file with error handling
-	file, err := os.Open(filename)
+// exitStatus is returned by modes that fail without an error to report, such as
+// -check finding a dirty input, to exit with a specific code and no message.
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// app carries the output streams and diagnostics settings of one run.
+type app struct {
+	stdout, stderr io.Writer
+	// logger receives the timing diagnostics, which are logged at debug level.
+	logger *slog.Logger
+	// color highlights the answers in text output with ANSI escapes.
+	color bool
+	// stopCPUProfile flushes an active CPU profile; it is a no-op when none is running.
+	stopCPUProfile func()
+	// stopTrace flushes an active execution trace; it is a no-op when none is running.
+	stopTrace func()
+}
+
+// startCPUProfile starts writing a CPU profile to path.
+func (a *app) startCPUProfile(path string) error {
+	file, err := os.Create(path)
 	if err != nil {
-		return 0, fmt.Errorf("error opening file: %v", err)
+		return fmt.Errorf("error creating CPU profile: %v", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error starting CPU profile: %v", err)
+	}
+	a.stopCPUProfile = func() {
+		pprof.StopCPUProfile()
+		file.Close()
+		a.stopCPUProfile = func() {}
+	}
+	return nil
+}
+
+// startTrace starts writing a runtime execution trace to path.
+func (a *app) startTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating trace: %v", err)
+	}
+	if err := trace.Start(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error starting trace: %v", err)
+	}
+	a.stopTrace = func() {
+		trace.Stop()
+		file.Close()
+		a.stopTrace = func() {}
+	}
+	return nil
+}
+
+// writeMemProfile writes a heap profile to path after forcing a collection so the
+// profile reflects live memory.
+func writeMemProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating memory profile: %v", err)
 	}
 	defer file.Close()
 
-	// Initialize frequency map for right-side numbers with capacity hint
-	rightFreq := make(map[int]int, 1000)
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("error writing memory profile: %v", err)
+	}
+	return nil
+}
+
+// answerColor is the ANSI escape that starts highlighted answers: bold green.
+const answerColor = "\x1b[1;32m"
+
+// highlight formats an answer, wrapped in answerColor when color is enabled.
+func (a *app) highlight(value int64) string {
+	if !a.color {
+		return strconv.FormatInt(value, 10)
+	}
+	return answerColor + strconv.FormatInt(value, 10) + "\x1b[0m"
+}
+
+// useColor resolves a -color mode. auto colors only a terminal stdout, and
+// honors the NO_COLOR convention.
+func useColor(mode string, stdout io.Writer, output string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if output != "" || os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		file, ok := stdout.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := file.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("invalid -color %q: use auto, always or never", mode)
+}
+
+// newLogger returns the diagnostics logger: text records on w at info level, or
+// at debug level, which adds the timings, when verbose is set.
+func newLogger(w io.Writer, verbose bool) *slog.Logger {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// inputEnv names the environment variable that supplies the input path when
+// -input is not given.
+const inputEnv = "AOC_INPUT"
+
+// defaultInput returns the -input default. The precedence is the -input flag,
+// then $AOC_INPUT, then ../puzzle_input.txt.
+func defaultInput() string {
+	if path := os.Getenv(inputEnv); path != "" {
+		return path
+	}
+	return "../puzzle_input.txt"
+}
+
+// parseDelimiter validates the -delimiter flag value, accepting a literal or escaped tab.
+func parseDelimiter(value string) (string, error) {
+	switch value {
+	case "", ",":
+		return value, nil
+	case "\\t", "\t":
+		return "\t", nil
+	}
+	return "", fmt.Errorf("unsupported delimiter %q: use \",\" or \"\\t\"", value)
+}
+
+// parseFieldIndices validates the -cols flag value, two comma-separated zero-based
+// field indices. An empty value keeps the default of exactly two fields.
+func parseFieldIndices(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid -cols %q: use two indices such as 1,3", value)
+	}
+
+	indices := make([]int, 2)
+	for i, part := range parts {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid -cols %q: indices must be non-negative integers", value)
+		}
+		indices[i] = index
+	}
+	return indices, nil
+}
+
+// parseComment validates the -comment flag value, which must be a single character.
+func parseComment(value string) (rune, error) {
+	if value == "" {
+		return 0, nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || unicode.IsSpace(r) {
+		return 0, fmt.Errorf("invalid comment character %q: use a single non-space character", value)
+	}
+	return r, nil
+}
+
+// loadInputs parses every path in the comma-separated input list, keeping the
+// columns of each file separate. On error the parts parsed so far, including the
+// partial columns of the failing file, are returned with it.
+func loadInputs(ctx context.Context, input string, opts day01.ParseOptions) ([]string, []*day01.Columns, error) {
+	paths := strings.Split(input, ",")
+	parts := make([]*day01.Columns, 0, len(paths))
+	for _, path := range paths {
+		cols, err := day01.ParseColumnsContext(ctx, path, opts)
+		if err != nil {
+			if cols != nil {
+				parts = append(parts, cols)
+			}
+			if len(paths) > 1 {
+				return paths, parts, fmt.Errorf("%s: %w", path, err)
+			}
+			return paths, parts, err
+		}
+		parts = append(parts, cols)
+	}
+	return paths, parts, nil
+}
+
+// parseInput parses and concatenates the columns of every input file, reporting
+// parse timing and skipped lines per file. When ctx is cancelled it reports the
+// partial progress instead.
+func (a *app) parseInput(ctx context.Context, input string, opts day01.ParseOptions) (*day01.Columns, error) {
+	parseStart := time.Now()
+	paths, parts, err := loadInputs(ctx, input, opts)
+	if errors.Is(err, context.Canceled) {
+		partial := day01.MergeColumns(parts...)
+		fmt.Fprintf(a.stderr, "Interrupted after %d lines parsed, %d distinct right values counted so far\n",
+			partial.Lines, len(day01.Frequencies(partial.Right)))
+		return nil, exitStatus(exitInterrupted)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Debug("parse complete", "elapsed", time.Since(parseStart))
 
-	// Use larger buffer size for potentially better IO performance
-	scanner := bufio.NewScanner(file)
-	buf := make([]byte, 64*1024)
-	scanner.Buffer(buf, 64*1024)
+	if len(parts) == 1 {
+		a.reportSkipped("", parts[0])
+	} else {
+		for i, cols := range parts {
+			a.logger.Debug("input parsed", "path", paths[i], "lines", cols.Lines, "comments", cols.Comments)
+			a.reportSkipped(paths[i]+": ", cols)
+		}
+	}
+	return day01.MergeColumns(parts...), nil
+}
 
+// dataEscapes interprets the escapes accepted in -data so multi-line input fits
+// in one shell argument.
+var dataEscapes = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// parseData parses the literal input given with -data.
+func (a *app) parseData(data string, opts day01.ParseOptions) (*day01.Columns, error) {
 	parseStart := time.Now()
+	cols, err := day01.ReadColumns(strings.NewReader(dataEscapes.Replace(data)), opts)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Debug("parse complete", "elapsed", time.Since(parseStart))
+	a.reportSkipped("", cols)
+	return cols, nil
+}
 
-	// First pass: Build frequency map of right-side numbers
-	for scanner.Scan() {
-		nums := strings.Fields(scanner.Text())
-		if len(nums) != 2 {
-			continue
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// parseSeparate reads the left and right lists from their own files.
+func (a *app) parseSeparate(leftPath, rightPath string, opts day01.ParseOptions) (*day01.Columns, error) {
+	if leftPath == "" || rightPath == "" {
+		return nil, fmt.Errorf("-left and -right must be given together")
+	}
+
+	parseStart := time.Now()
+	cols, err := day01.ParseSeparateColumns(leftPath, rightPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Debug("parse complete", "elapsed", time.Since(parseStart))
+	return cols, nil
+}
+
+// progressInterval is how often -progress reports on stderr.
+const progressInterval = time.Second
+
+// startProgress reports on stderr how far parsing of input has got, every
+// progressInterval, until the returned stop function is called. stop waits for
+// the reporting goroutine to exit.
+func (a *app) startProgress(input string, opts day01.ParseOptions) (stop func()) {
+	total := inputSize(input, opts)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			// Without a comparable size, e.g. for stdin, fall back to a line count
+			if total > 0 {
+				percent := min(100, 100*float64(opts.Progress.Bytes())/float64(total))
+				fmt.Fprintf(a.stderr, "Progress: %.1f%%\n", percent)
+			} else {
+				fmt.Fprintf(a.stderr, "Progress: %d lines\n", opts.Progress.Lines())
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// inputSize returns the combined size of the input files, or 0 when any of them
+// is stdin or compressed, since bytes parsed cannot be compared to its size.
+func inputSize(input string, opts day01.ParseOptions) int64 {
+	var total int64
+	for _, path := range strings.Split(input, ",") {
+		if path == "-" || opts.Gzip || strings.HasSuffix(path, ".gz") {
+			return 0
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			return 0
 		}
+		total += info.Size()
+	}
+	return total
+}
 
-		rightNum, err := strconv.Atoi(nums[1])
+// reportSkipped warns on stderr when malformed lines were skipped, listing the
+// sampled ones.
+func (a *app) reportSkipped(prefix string, cols *day01.Columns) {
+	if cols.Headers > 0 {
+		fmt.Fprintf(a.stderr, "%sDetected and skipped an apparent header row\n", prefix)
+	}
+	if cols.Skipped > 0 {
+		fmt.Fprintf(a.stderr, "%sSkipped %d malformed lines\n", prefix, cols.Skipped)
+	}
+	for _, skipped := range cols.SkippedLines {
+		fmt.Fprintf(a.stderr, "%s  line %d: %v\n", prefix, skipped.Line, skipped.Err)
+	}
+}
+
+// runCheck validates every input file without solving and prints a report for each.
+// It returns false if any file would fail in strict mode.
+func (a *app) runCheck(input string, opts day01.ParseOptions) (bool, error) {
+	clean := true
+	for _, path := range strings.Split(input, ",") {
+		report, err := day01.CheckFile(path, opts)
 		if err != nil {
-			continue
+			return false, fmt.Errorf("%s: %w", path, err)
+		}
+
+		balanced := "yes"
+		if !report.Balanced() {
+			balanced = "no"
+		}
+		fmt.Fprintf(a.stdout, "%s: %d lines, %d skipped, %d comments\n", path, report.Lines, report.Skipped, report.Comments)
+		if report.Headers > 0 {
+			fmt.Fprintln(a.stdout, "  header row: skipped")
 		}
-		rightFreq[rightNum]++
+		fmt.Fprintf(a.stdout, "  left:  %d values, min %d, max %d\n", report.LeftCount, report.LeftMin, report.LeftMax)
+		fmt.Fprintf(a.stdout, "  right: %d values, min %d, max %d\n", report.RightCount, report.RightMin, report.RightMax)
+		fmt.Fprintf(a.stdout, "  balanced: %s\n", balanced)
+		clean = clean && report.Clean()
 	}
+	return clean, nil
+}
 
-	fmt.Printf("Parsing first pass completed in %v\n", time.Since(parseStart))
+// solveInput parses every file in the comma-separated input list and computes
+// both answers over their concatenated columns.
+func solveInput(input string, opts day01.ParseOptions) (*day01.Result, error) {
+	_, parts, err := loadInputs(context.Background(), input, opts)
+	if err != nil {
+		return nil, err
+	}
+	cols := day01.MergeColumns(parts...)
+	return day01.NewResult(cols, day01.Frequencies(cols.Right)), nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return 0, fmt.Errorf("error reading file: %v", err)
+// runCompare solves input and other and prints both answers side by side.
+// It returns false if either answer differs.
+func (a *app) runCompare(input, other string, opts day01.ParseOptions) (bool, error) {
+	res, err := solveInput(input, opts)
+	if err != nil {
+		return false, err
+	}
+	otherRes, err := solveInput(other, opts)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", other, err)
 	}
 
-	// Reset file for second pass
-	calcStart := time.Now()
-	file.Seek(0, 0)
-	scanner = bufio.NewScanner(file)
-	scanner.Buffer(buf, 64*1024)
+	a.printComparison("Part 1 (total distance)", res.TotalDistance, otherRes.TotalDistance)
+	a.printComparison("Part 2 (similarity score)", res.SimilarityScore, otherRes.SimilarityScore)
+	return res.TotalDistance == otherRes.TotalDistance &&
+		res.SimilarityScore == otherRes.SimilarityScore, nil
+}
 
-	// Second pass: Calculate similarity score
-	var totalScore int64
+// expectation is an expected answer given on the command line; set records
+// whether the flag was used, since any int64 is a valid answer.
+type expectation struct {
+	value int64
+	set   bool
+}
 
-	for scanner.Scan() {
-		nums := strings.Fields(scanner.Text())
-		if len(nums) != 2 {
-			continue
+func (e *expectation) String() string {
+	if !e.set {
+		return ""
+	}
+	return strconv.FormatInt(e.value, 10)
+}
+
+func (e *expectation) Set(s string) error {
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("expected an integer answer")
+	}
+	e.value, e.set = value, true
+	return nil
+}
+
+// verifyAnswers compares res with the expected answers that were given, printing
+// a MISMATCH line for each difference or OK when everything matches.
+func (a *app) verifyAnswers(res *day01.Result, distance, score expectation) error {
+	ok := true
+	check := func(label string, got int64, want expectation) {
+		if want.set && got != want.value {
+			fmt.Fprintf(a.stdout, "MISMATCH: %s got %d want %d\n", label, got, want.value)
+			ok = false
 		}
+	}
+	check("part 1 (total distance)", res.TotalDistance, distance)
+	check("part 2 (similarity score)", res.SimilarityScore, score)
+
+	if !ok {
+		return exitStatus(exitMismatch)
+	}
+	fmt.Fprintln(a.stdout, "OK")
+	return nil
+}
+
+// printComparison writes one labelled pair of answers and whether they match.
+func (a *app) printComparison(label string, got, other int64) {
+	verdict := "match"
+	if got != other {
+		verdict = "differ"
+	}
+	fmt.Fprintf(a.stdout, "%s: %d vs %d (%s)\n", label, got, other, verdict)
+}
+
+// watchInterval is how often -watch polls the input files for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watch re-solves input and prints a timestamped answer line whenever any of its
+// files changes, until interrupted. Solve errors are reported and watching goes on,
+// since the file is usually mid-edit.
+func (a *app) watch(input string, opts day01.ParseOptions, part int) error {
+	if input == "-" {
+		return fmt.Errorf("-watch needs a file input, stdin cannot be polled")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	paths := strings.Split(input, ",")
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
 
-		leftNum, err := strconv.Atoi(nums[0])
+	last := ""
+	for {
+		// Poll modification times and sizes rather than depending on fsnotify
+		if current := fingerprint(paths); current != last {
+			last = current
+			a.printWatched(input, opts, part)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// interruptContext returns a context cancelled by the first os.Interrupt. The
+// handler is then removed, so a second interrupt terminates the process as usual.
+// The returned stop function removes the handler if no interrupt arrived.
+func interruptContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	go func() {
+		select {
+		case <-sigs:
+			signal.Stop(sigs)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigs)
+		cancel()
+	}
+}
+
+// fingerprint summarizes the modification time and size of every path, so any
+// edit, truncation, removal or re-creation changes it.
+func fingerprint(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
 		if err != nil {
+			fmt.Fprintf(&b, "%s:missing;", path)
 			continue
 		}
+		fmt.Fprintf(&b, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String()
+}
+
+// printWatched solves input once and prints the selected answers on one line.
+func (a *app) printWatched(input string, opts day01.ParseOptions, part int) {
+	stamp := time.Now().Format(time.TimeOnly)
+	res, err := solveInput(input, opts)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "[%s] Error: %v\n", stamp, err)
+		return
+	}
+
+	answers := make([]string, 0, 2)
+	if part != 2 {
+		answers = append(answers, "Part 1 (total distance): "+a.highlight(res.TotalDistance))
+	}
+	if part != 1 {
+		answers = append(answers, "Part 2 (similarity score): "+a.highlight(res.SimilarityScore))
+	}
+	if res.LinesSkipped > 0 {
+		answers = append(answers, fmt.Sprintf("%d malformed lines skipped", res.LinesSkipped))
+	}
+	fmt.Fprintf(a.stdout, "[%s] %s\n", stamp, strings.Join(answers, ", "))
+}
+
+// streamSortedDistance computes the total distance from two pre-sorted files
+// without loading either into memory.
+func (a *app) streamSortedDistance(leftPath, rightPath string) (int64, error) {
+	if leftPath == "" || rightPath == "" {
+		return 0, fmt.Errorf("-sorted-left and -sorted-right must be given together")
+	}
+
+	left, err := day01.OpenInput(leftPath)
+	if err != nil {
+		return 0, err
+	}
+	defer left.Close()
+
+	right, err := day01.OpenInput(rightPath)
+	if err != nil {
+		return 0, err
+	}
+	defer right.Close()
+
+	start := time.Now()
+	distance, err := day01.StreamTotalDistance(left, right)
+	a.logger.Debug("streaming complete", "elapsed", time.Since(start))
+	return distance, err
+}
+
+// timeRepeated solves the input n times, re-reading every file on each iteration,
+// and returns the elapsed time of each run. A single sequentially parsed file goes
+// through a reused day01.Solver so allocation does not skew the timings. each, when
+// set, is called after every run with its index counting from 1, its result and its
+// elapsed time.
+func timeRepeated(input string, opts day01.ParseOptions, n int, each func(iteration int, res *day01.Result, elapsed time.Duration) error) ([]time.Duration, error) {
+	solver := day01.NewSolver(opts)
+	reuse := !strings.Contains(input, ",") && opts.Workers <= 1
+
+	durations := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		var res *day01.Result
+		var err error
+		if reuse {
+			res, err = solver.SolveFile(input)
+		} else {
+			res, err = solveInput(input, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		elapsed := time.Since(start)
+		durations = append(durations, elapsed)
+		if each != nil {
+			if err := each(i+1, res, elapsed); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return durations, nil
+}
+
+// benchSuiteSizes are the line counts of the generated -bench-suite inputs.
+var benchSuiteSizes = []int{1_000, 10_000, 100_000}
+
+// benchSuiteSeed fixes the -bench-suite inputs so reports are comparable across runs.
+const benchSuiteSeed = 1
+
+// runBenchSuite solves a generated input of each benchSuiteSizes size and prints
+// one line per size with the elapsed time and throughput. Inputs are generated
+// in memory before timing starts, so only parsing and solving are measured.
+func (a *app) runBenchSuite(opts day01.ParseOptions) error {
+	for _, lines := range benchSuiteSizes {
+		var buf bytes.Buffer
+		if err := day01.Generate(&buf, lines, benchSuiteSeed); err != nil {
+			return err
+		}
 
-		// Multiply left number by its frequency in right list
-		totalScore += int64(leftNum) * int64(rightFreq[leftNum])
+		start := time.Now()
+		if _, err := day01.Solve(bytes.NewReader(buf.Bytes()), opts); err != nil {
+			return err
+		}
+		elapsed := time.Since(start)
+		fmt.Fprintf(a.stdout, "%7d lines  %12v  %12.0f lines/s\n", lines, elapsed, float64(lines)/elapsed.Seconds())
+	}
+	return nil
+}
+
+// printTimings writes the min, max, mean and p50/p90/p99 of durations to stderr.
+func (a *app) printTimings(durations []time.Duration) {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	mean := sum / time.Duration(len(sorted))
+	fmt.Fprintf(a.stderr, "Runs: %d, min: %v, max: %v, mean: %v\n", len(sorted), sorted[0], sorted[len(sorted)-1], mean)
+	fmt.Fprintf(a.stderr, "p50: %v, p90: %v, p99: %v\n",
+		percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99))
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	// Nearest rank: the smallest value with at least p% of runs at or below it
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// printStats writes the frequency statistics and the skipped line breakdown to stderr.
+func (a *app) printStats(stats day01.Stats, cols *day01.Columns, windowed bool) {
+	if windowed {
+		fmt.Fprintf(a.stderr, "Lines in window: %d, after skipping %d\n", cols.Lines, cols.Before)
+	}
+	fmt.Fprintf(a.stderr, "Unique right values: %d\n", stats.UniqueRight)
+	fmt.Fprintf(a.stderr, "Most frequent right value: %d (%d times)\n", stats.MostFrequent, stats.MostFrequentCount)
+	fmt.Fprintf(a.stderr, "Left values with no match: %d\n", stats.UnmatchedLeft)
+	fmt.Fprintf(a.stderr, "Input order: left %s, right %s\n", orderLabel(cols.LeftSorted()), orderLabel(cols.RightSorted()))
+	fmt.Fprintf(a.stderr, "Skipped lines: %d blank, %d wrong field count, %d non-integer, %d out of range\n",
+		cols.Skips.Blank, cols.Skips.FieldCount, cols.Skips.NotInteger, cols.Skips.OutOfRange)
+	if cols.Padded > 0 {
+		fmt.Fprintf(a.stderr, "Padded values: %d\n", cols.Padded)
+	}
+}
+
+// orderLabel describes whether a column arrived sorted, for -stats.
+func orderLabel(sorted bool) string {
+	if sorted {
+		return "already sorted"
+	}
+	return "unsorted"
+}
+
+// histogramWidth caps the length of the longest -histogram bar.
+const histogramWidth = 50
+
+// printHistogram writes an ASCII bar chart of the frequency buckets to stderr,
+// scaling the bars so the largest bucket is histogramWidth wide.
+func (a *app) printHistogram(buckets []day01.HistogramBucket) {
+	largest := 0
+	for _, b := range buckets {
+		largest = max(largest, b.Values)
+	}
+
+	fmt.Fprintln(a.stderr, "Right values by occurrence count:")
+	for _, b := range buckets {
+		// Round up so every non-empty bucket shows at least one mark
+		bar := (b.Values*histogramWidth + largest - 1) / largest
+		fmt.Fprintf(a.stderr, "%4dx | %-*s %d\n", b.Frequency, histogramWidth, strings.Repeat("#", bar), b.Values)
+	}
+}
+
+// printTopPairs writes the pairs contributing the most distance to stderr.
+func (a *app) printTopPairs(pairs []day01.Pair) {
+	fmt.Fprintf(a.stderr, "Top %d pairs by distance:\n", len(pairs))
+	for _, p := range pairs {
+		fmt.Fprintf(a.stderr, "  %d - %d = %d\n", p.Left, p.Right, p.Distance)
+	}
+}
+
+// printLineContext shows the input line a parse error stopped at on stderr, with
+// a caret under the offending token. Errors without a line are left to the usual
+// error report.
+func (a *app) printLineContext(err error) {
+	loc, ok := day01.ErrorContext(err)
+	if !ok {
+		return
+	}
+	prefix := fmt.Sprintf("line %d: ", loc.Line)
+	// Keep tabs in the padding so the caret lines up however tabs are rendered
+	pad := []byte(strings.Repeat(" ", len(prefix)))
+	for _, c := range []byte(loc.Text[:loc.Column]) {
+		if c == '\t' {
+			pad = append(pad, '\t')
+		} else {
+			pad = append(pad, ' ')
+		}
+	}
+	fmt.Fprintf(a.stderr, "%s%s\n%s^\n", prefix, loc.Text, pad)
+}
+
+// solveWithinBudget checks the estimated memory of the in-memory solve of input
+// against budgetMB. Over budget it computes the distance with an external sort
+// and reports true, refusing part 2, which needs every value in memory at once.
+// Within budget it reports false and the usual solve goes ahead.
+func (a *app) solveWithinBudget(input string, opts day01.ParseOptions, budgetMB int64, part int) (int64, bool, error) {
+	if input == "-" || strings.Contains(input, ",") || strings.Contains(input, "://") {
+		return 0, false, fmt.Errorf("-max-memory-mb estimates memory from the file size and needs a single file -input")
+	}
+	info, err := os.Stat(input)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, false, fmt.Errorf("%w: %s", day01.ErrFileNotFound, input)
+		}
+		return 0, false, fmt.Errorf("error reading input: %v", err)
 	}
 
-	fmt.Printf("Calculation completed in %v\n", time.Since(calcStart))
-	fmt.Printf("Total time: %v\n", time.Since(start))
+	budget := budgetMB << 20
+	estimate := day01.EstimateMemory(info.Size())
+	a.logger.Debug("memory estimate", "bytes", estimate, "budget", budget)
+	if estimate <= budget {
+		return 0, false, nil
+	}
 
-	return totalScore, nil
+	estimateMB := (estimate + 1<<20 - 1) >> 20
+	if part == 2 {
+		return 0, false, fmt.Errorf("the similarity score needs about %d MB in memory, over the -max-memory-mb budget of %d", estimateMB, budgetMB)
+	}
+	fmt.Fprintf(a.stderr, "Warning: the input needs about %d MB in memory, over the -max-memory-mb budget of %d; computing only the total distance with an external sort\n", estimateMB, budgetMB)
+	// Each column buffers 8-byte values, so half the budget goes to each
+	distance, err := day01.ExternalTotalDistance(input, opts, int(budget/16))
+	if err != nil {
+		return 0, false, err
+	}
+	return distance, true, nil
+}
+
+// frequencies counts the right values for the solve. Tests replace it with a
+// faulty count to check that -paranoid catches the wrong score.
+var frequencies = day01.Frequencies
+
+// crossCheckSimilarity recomputes the similarity score of cols with the sorted
+// merge sweep and returns an error when it differs from score, which means a bug
+// in one of the two methods. The solve has already sorted the columns, so the
+// extra cost is one pass over each.
+func crossCheckSimilarity(cols *day01.Columns, score int64) error {
+	merged := day01.SimilarityScoreSorted(cols.Left, cols.Right)
+	if merged != score {
+		return fmt.Errorf("paranoid check failed: similarity score %d from the frequency map, %d from the sorted merge", score, merged)
+	}
+	return nil
+}
+
+// maxExplainPairs caps -explain, whose walkthrough is one line per pair.
+const maxExplainPairs = 100
+
+// printExplanation walks through the similarity score on stderr, one left value
+// per line with its running total.
+func (a *app) printExplanation(contributions []day01.Contribution) {
+	fmt.Fprintln(a.stderr, "Similarity score, left value × frequency in the right list:")
+	for _, c := range contributions {
+		fmt.Fprintf(a.stderr, "  %d × %d = %d (total %d)\n", c.Left, c.Frequency, c.Product, c.Total)
+	}
+}
+
+// jsonAnswer is the machine-readable form of the answers printed by -format json.
+// A part not selected with -part is omitted.
+type jsonAnswer struct {
+	Day       int    `json:"day"`
+	Part1     *int64 `json:"part1,omitempty"`
+	Part2     *int64 `json:"part2,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+	ParseMs   int64  `json:"parse_ms"`
+	CalcMs    int64  `json:"calc_ms"`
+}
+
+// writeOutput calls write with stdout, or with path created or truncated when it
+// is set, reporting any error from closing the file.
+func (a *app) writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(a.stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing output file: %v", err)
+	}
+	return nil
+}
+
+// printAnswers writes the answers to w in the requested format. part selects a
+// single part to print, or both when zero.
+func (a *app) printAnswers(w io.Writer, format string, part int, res *day01.Result) error {
+	switch format {
+	case "text":
+		if part != 2 {
+			if _, err := fmt.Fprintf(w, "Part 1 (total distance): %s\n", a.highlight(res.TotalDistance)); err != nil {
+				return err
+			}
+		}
+		if part != 1 {
+			if _, err := fmt.Fprintf(w, "Part 2 (similarity score): %s\n", a.highlight(res.SimilarityScore)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "plain":
+		if part != 2 {
+			if _, err := fmt.Fprintln(w, res.TotalDistance); err != nil {
+				return err
+			}
+		}
+		if part != 1 {
+			if _, err := fmt.Fprintln(w, res.SimilarityScore); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "kv":
+		return writeKeyValues(w, part, res)
+	case "table":
+		return writeTable(w, part, res)
+	case "json":
+		answer := jsonAnswer{
+			Day:       1,
+			ElapsedMs: res.Elapsed.Milliseconds(),
+			ParseMs:   res.ParseElapsed.Milliseconds(),
+			CalcMs:    res.CalcElapsed.Milliseconds(),
+		}
+		if part != 2 {
+			answer.Part1 = &res.TotalDistance
+		}
+		if part != 1 {
+			answer.Part2 = &res.SimilarityScore
+		}
+		return json.NewEncoder(w).Encode(answer)
+	}
+	return fmt.Errorf("unsupported format %q: use text, plain, kv, table or json", format)
+}
+
+// writeKeyValues writes the answers and line accounting as key=value lines sorted
+// by key. Timings are left out so the output is byte-identical across runs and
+// machines, ready for diffing.
+func writeKeyValues(w io.Writer, part int, res *day01.Result) error {
+	type keyValue struct {
+		key   string
+		value int64
+	}
+	pairs := []keyValue{
+		{"lines_commented", int64(res.LinesCommented)},
+		{"lines_parsed", int64(res.LinesParsed)},
+		{"lines_skipped", int64(res.LinesSkipped)},
+	}
+	if part != 2 {
+		pairs = append(pairs, keyValue{"distance", res.TotalDistance})
+	}
+	if part != 1 {
+		pairs = append(pairs, keyValue{"similarity", res.SimilarityScore})
+	}
+	slices.SortFunc(pairs, func(a, b keyValue) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	for _, kv := range pairs {
+		if _, err := fmt.Fprintf(w, "%s=%d\n", kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printWindows writes one line per -window window: its pair range and score, or
+// the bare score in plain format. Pair numbers count from 1.
+func printWindows(w io.Writer, format string, windows []day01.Window) error {
+	for _, win := range windows {
+		var err error
+		if format == "plain" {
+			_, err = fmt.Fprintln(w, win.Score)
+		} else {
+			_, err = fmt.Fprintf(w, "Pairs %d-%d: %d\n", win.Start+1, win.End, win.Score)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rememberedAnswers is the file format of -remember.
+type rememberedAnswers struct {
+	Distance   int64 `json:"distance"`
+	Similarity int64 `json:"similarity"`
+}
+
+// rememberAnswers reports on stderr how res differs from the answers recorded in
+// path by a previous run, if any, and then records res there.
+func (a *app) rememberAnswers(path string, res *day01.Result) error {
+	current := rememberedAnswers{Distance: res.TotalDistance, Similarity: res.SimilarityScore}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var previous rememberedAnswers
+		if err := json.Unmarshal(data, &previous); err != nil {
+			return fmt.Errorf("error reading remembered answers from %s: %v", path, err)
+		}
+		a.printChange("distance", previous.Distance, current.Distance)
+		a.printChange("similarity", previous.Similarity, current.Similarity)
+	case errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintf(a.stderr, "Recording answers in %s\n", path)
+	default:
+		return fmt.Errorf("error reading remembered answers: %v", err)
+	}
+
+	data, err = json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error recording answers: %v", err)
+	}
+	return nil
+}
+
+// printChange writes one -remember comparison line to stderr.
+func (a *app) printChange(name string, previous, current int64) {
+	status := "unchanged"
+	if previous != current {
+		status = "changed"
+	}
+	fmt.Fprintf(a.stderr, "%s: %d -> %d, %s\n", name, previous, current, status)
+}
+
+// printGroupSums writes the -groupsum totals in ascending key order, one key per
+// line: "key: total", or "key total" in plain format.
+func printGroupSums(w io.Writer, format string, sums map[int64]int64) error {
+	keys := make([]int64, 0, len(sums))
+	for key := range sums {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	layout := "%d: %d\n"
+	if format == "plain" {
+		layout = "%d %d\n"
+	}
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, layout, key, sums[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpSorted writes the columns of cols, already sorted by the solve, to
+// prefix.left.txt and prefix.right.txt with one value per line.
+func (a *app) dumpSorted(prefix string, cols *day01.Columns) error {
+	for _, column := range []struct {
+		side   string
+		values []int64
+	}{{"left", cols.Left}, {"right", cols.Right}} {
+		err := a.writeOutput(prefix+"."+column.side+".txt", func(w io.Writer) error {
+			return writeValues(w, column.values)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeValues writes values to w, one per line.
+func writeValues(w io.Writer, values []int64) error {
+	bw := bufio.NewWriter(w)
+	line := make([]byte, 0, 24)
+	for _, v := range values {
+		line = strconv.AppendInt(line[:0], v, 10)
+		line = append(line, '\n')
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// dirResult is the outcome of solving one input file in -dir mode.
+type dirResult struct {
+	name string
+	res  *day01.Result
+	err  error
+}
+
+// solveDir solves every *.txt file in dir, at most workers at a time, and returns
+// the outcomes sorted by file name. A file that fails to solve is reported in its
+// result rather than stopping the others. ready, when set, is called with each
+// outcome in file name order as soon as it and every earlier one are done; an
+// error from it is returned once the remaining files finish.
+func solveDir(dir string, opts day01.ParseOptions, workers int, ready func(dirResult) error) ([]dirResult, error) {
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", day01.ErrFileNotFound, dir)
+		}
+		return nil, fmt.Errorf("error reading -dir: %v", err)
+	}
+	// Glob returns the matches in lexical order
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading -dir: %v", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.txt inputs in %s", dir)
+	}
+
+	results := make([]dirResult, len(paths))
+	done := make([]chan struct{}, len(paths))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		done[i] = make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			res, err := day01.SolveFile(path, opts)
+			results[i] = dirResult{name: filepath.Base(path), res: res, err: err}
+		}()
+	}
+
+	var readyErr error
+	if ready != nil {
+		for i := range results {
+			<-done[i]
+			if readyErr = ready(results[i]); readyErr != nil {
+				break
+			}
+		}
+	}
+	wg.Wait()
+	return results, readyErr
+}
+
+// ndjsonLine is one line of -format ndjson: the outcome of one -dir input, named
+// by File, or of one -repeat iteration, numbered from 1 by Iteration. A failed
+// input carries only File and Error; the answers are pointers so that zero
+// answers are still written for solved ones.
+type ndjsonLine struct {
+	File       string `json:"file,omitempty"`
+	Iteration  int    `json:"iteration,omitempty"`
+	Distance   *int64 `json:"distance,omitempty"`
+	Similarity *int64 `json:"similarity,omitempty"`
+	ElapsedNs  *int64 `json:"elapsed_ns,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// withAnswers returns l with the answers and elapsed time of a solve.
+func (l ndjsonLine) withAnswers(distance, similarity int64, elapsed time.Duration) ndjsonLine {
+	ns := int64(elapsed)
+	l.Distance, l.Similarity, l.ElapsedNs = &distance, &similarity, &ns
+	return l
+}
+
+// printDirTable writes one aligned row per -dir input, with the error in place of
+// the answers for files that failed.
+func printDirTable(w io.Writer, results []dirResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "File\tDistance\tSimilarity\tElapsed")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(tw, "%s\terror: %v\n", r.name, r.err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\n", r.name, r.res.TotalDistance, r.res.SimilarityScore, r.res.Elapsed.Round(time.Microsecond))
+	}
+	return tw.Flush()
+}
+
+// writeTable writes the answers, line accounting and elapsed time as an aligned
+// two-column table of metric names and values.
+func writeTable(w io.Writer, part int, res *day01.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if part != 2 {
+		fmt.Fprintf(tw, "Total distance\t%d\n", res.TotalDistance)
+	}
+	if part != 1 {
+		fmt.Fprintf(tw, "Similarity score\t%d\n", res.SimilarityScore)
+	}
+	fmt.Fprintf(tw, "Lines parsed\t%d\n", res.LinesParsed)
+	fmt.Fprintf(tw, "Lines skipped\t%d\n", res.LinesSkipped)
+	fmt.Fprintf(tw, "Elapsed\t%v\n", res.Elapsed)
+	return tw.Flush()
 }
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses args, executes the selected mode and returns the process exit code.
+// All output goes to stdout and stderr.
+func run(args []string, stdout, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr, logger: newLogger(stderr, false), stopCPUProfile: func() {}, stopTrace: func() {}}
+	err := a.run(args)
+	a.stopCPUProfile()
+	a.stopTrace()
+	if err == nil {
+		return 0
+	}
+
+	var status exitStatus
+	if errors.As(err, &status) {
+		return int(status)
+	}
+	fmt.Fprintf(stderr, "Error: %v\n", err)
+	return exitCode(err)
+}
+
+// config holds the parsed command-line flags of one run.
+type config struct {
+	input, data, delimiter, comment, fieldCols string
+	leftFile, rightFile                        string
+	sortedLeft, sortedRight                    string
+	windowFreq, colorMode, format, output      string
+	dumpPrefix, remember, dir, compare         string
+	cpuprofile, memprofile, tracePath          string
+
+	retries, workers, base, showSkipped int
+	skipLines, headLines, minLines      int
+	repeat, window, step, top, part     int
+	generate                            int
+
+	maxValue, padValue, since, maxMemoryMB, shuffle, seed int64
+
+	timeout time.Duration
+
+	strict, failFast, parallel, mmap, clean, pad, weighted bool
+	nonneg, gzipInput, stats, histogram, groupSum          bool
+	checksum, explain, quiet, showProgress, watch, check   bool
+	benchSuite, dedupLeft, paranoid, dense, swap, verbose  bool
+	// timestamped records whether -since was given, since any timestamp is valid.
+	timestamped bool
+
+	expectScore, expectDistance expectation
+}
+
+// usageError reports an invalid flag value or combination of flags.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// parseConfig parses args and checks the flags fit together. Parse failures are
+// reported to stderr by the flag package.
+func parseConfig(args []string, stderr io.Writer) (*config, error) {
+	c := &config{}
+	flags := flag.NewFlagSet("day01", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&c.input, "input", defaultInput(), "comma-separated puzzle input files or http(s) URLs (.gz is decompressed), or - for stdin; defaults to $"+inputEnv+" when set")
+	flags.IntVar(&c.retries, "retries", 0, "retry opening a file or URL input up to N times, with backoff, on transient failures such as network or server errors")
+	flags.DurationVar(&c.timeout, "timeout", 30*time.Second, "time limit for fetching an http(s) -input; 0 means none")
+	flags.StringVar(&c.data, "data", "", "literal puzzle input instead of -input, with \\n and \\t escapes interpreted")
+	flags.BoolVar(&c.strict, "strict", false, "fail on malformed lines instead of skipping them")
+	flags.BoolVar(&c.failFast, "fail-fast", false, "like -strict, and print the failing line with a caret under the offending token")
+	flags.StringVar(&c.delimiter, "delimiter", "", "split columns on \",\" or \"\\t\" instead of any whitespace")
+	flags.BoolVar(&c.parallel, "parallel", false, "parse file input in parallel chunks")
+	flags.BoolVar(&c.mmap, "mmap", false, "read a regular input file through a memory mapping instead of buffered reads")
+	flags.IntVar(&c.workers, "workers", runtime.NumCPU(), "number of parallel workers")
+	flags.StringVar(&c.comment, "comment", "", "ignore lines starting with this character, e.g. #")
+	flags.IntVar(&c.base, "base", 10, "radix of the input values: 2, 8, 10 or 16 (hexadecimal values may start with 0x); answers are always decimal")
+	flags.BoolVar(&c.clean, "clean", false, "strip digit-grouping underscores and commas, e.g. 1_000 or 1,000, from values")
+	flags.IntVar(&c.showSkipped, "show-skipped", 5, "list up to N skipped lines with their line numbers on stderr")
+	flags.Int64Var(&c.maxValue, "max-value", 0, "skip lines holding a value above N, or fail on them with -strict; 0 means no limit")
+	flags.BoolVar(&c.pad, "pad", false, "pad the shorter column with -pad-value instead of failing when the columns differ in length")
+	flags.Int64Var(&c.padValue, "pad-value", 0, "value -pad appends to the shorter column")
+	flags.Int64Var(&c.since, "since", 0, "read a third column of unix timestamps and ignore lines stamped before this time")
+	flags.BoolVar(&c.weighted, "weighted", false, "read a third column of per-line weights and weight each left value's similarity contribution by it")
+	flags.StringVar(&c.fieldCols, "cols", "", "zero-based indices i,j of the left and right fields on lines with extra fields; default exactly two fields")
+	flags.IntVar(&c.skipLines, "skip", 0, "ignore the first N lines of each input")
+	flags.IntVar(&c.headLines, "head", 0, "solve only the first N lines after -skip; 0 means all")
+	flags.BoolVar(&c.nonneg, "nonneg", false, "reject negative values with an error naming the line")
+	flags.BoolVar(&c.gzipInput, "gzip", false, "decompress gzip input even without a .gz suffix")
+	flags.StringVar(&c.leftFile, "left", "", "file of left values, one per line; read with -right instead of -input")
+	flags.StringVar(&c.rightFile, "right", "", "file of right values, one per line; read with -left instead of -input")
+	flags.StringVar(&c.sortedLeft, "sorted-left", "", "pre-sorted file of left values, one per line; streams Part 1 with -sorted-right")
+	flags.StringVar(&c.sortedRight, "sorted-right", "", "pre-sorted file of right values, one per line; streams Part 1 with -sorted-left")
+	flags.IntVar(&c.minLines, "min-lines", 10, "warn when fewer lines than this are parsed; 0 disables the warning")
+	flags.IntVar(&c.repeat, "repeat", 1, "solve N times and report timing statistics to stderr")
+	flags.BoolVar(&c.stats, "stats", false, "print frequency statistics to stderr")
+	flags.BoolVar(&c.histogram, "histogram", false, "print a bar chart of how often right values repeat to stderr")
+	flags.BoolVar(&c.groupSum, "groupsum", false, "print the sum of the right values for each left key, sorted by key, instead of the answers")
+	flags.IntVar(&c.window, "window", 0, "print the similarity score of every W consecutive pairs instead of the answers")
+	flags.IntVar(&c.step, "step", 1, "pairs to advance between -window windows")
+	flags.StringVar(&c.windowFreq, "window-freq", "local", "frequencies -window scores against: local (right values in the same window) or global (the whole right list)")
+	flags.IntVar(&c.top, "top", 0, "print the N pairs contributing the most distance to stderr")
+	flags.BoolVar(&c.checksum, "checksum", false, "print an order-independent checksum of the parsed pairs to stderr")
+	flags.BoolVar(&c.explain, "explain", false, fmt.Sprintf("walk through the similarity score on stderr, for inputs of up to %d pairs", maxExplainPairs))
+	flags.IntVar(&c.part, "part", 0, "print only part 1 or part 2; both when unset")
+	flags.StringVar(&c.colorMode, "color", "auto", "highlight the answers in text output: auto (when stdout is a terminal), always or never")
+	flags.StringVar(&c.format, "format", "text", "output format: text, plain (bare numbers), kv (sorted key=value lines), table (aligned metrics), json, or ndjson (one line per -dir input or -repeat iteration)")
+	flags.BoolVar(&c.quiet, "quiet", false, "print only the bare answer numbers and no diagnostics; errors are still reported")
+	flags.StringVar(&c.output, "output", "", "write the answers to path instead of stdout")
+	flags.Var(&c.expectScore, "expect", "expected similarity score; print OK or MISMATCH instead of the answers")
+	flags.Var(&c.expectDistance, "expect-distance", "expected total distance, checked like -expect")
+	flags.StringVar(&c.dumpPrefix, "dump-sorted", "", "write the sorted columns to prefix.left.txt and prefix.right.txt, one value per line")
+	flags.StringVar(&c.remember, "remember", "", "compare the answers with those recorded in this JSON file, then record the new ones")
+	flags.StringVar(&c.dir, "dir", "", "solve every *.txt file in this directory, -workers at a time, and print a summary table")
+	flags.StringVar(&c.compare, "compare", "", "solve this file too and report whether its answers match -input's")
+	flags.BoolVar(&c.showProgress, "progress", false, "report parse progress on stderr every second")
+	flags.BoolVar(&c.watch, "watch", false, "re-solve and print the answers whenever the input changes, until Ctrl-C")
+	flags.BoolVar(&c.check, "check", false, "only validate the input and report its shape; exit non-zero if -strict would fail")
+	flags.BoolVar(&c.benchSuite, "bench-suite", false, "solve generated inputs of 1k, 10k and 100k lines and print the elapsed time and throughput of each")
+	flags.IntVar(&c.generate, "generate", 0, "write N random input lines to -output (or stdout) and exit without solving")
+	flags.BoolVar(&c.dedupLeft, "dedup-left", false, "count each distinct left value once in the similarity score, a puzzle variant with a different answer")
+	flags.Int64Var(&c.maxMemoryMB, "max-memory-mb", 0, "when the input is estimated to need more memory than this, compute only the distance with an external sort; 0 means no limit")
+	flags.BoolVar(&c.paranoid, "paranoid", false, "recompute the similarity score with a sorted merge and fail if it disagrees with the frequency map's")
+	flags.BoolVar(&c.dense, "dense", false, "count right values in a slice over their range instead of a map, when the range is small enough")
+	flags.BoolVar(&c.swap, "swap", false, "read the first column as the right list and the second as the left; the two puzzle answers are symmetric, -weighted, -groupsum and global -window results are not")
+	flags.Int64Var(&c.shuffle, "shuffle", 0, "shuffle the parsed lines with this seed before solving, to check the answers do not depend on order")
+	flags.Int64Var(&c.seed, "seed", 1, "random seed for -generate")
+	flags.StringVar(&c.cpuprofile, "cpuprofile", "", "write a CPU profile of the solve to path")
+	flags.StringVar(&c.memprofile, "memprofile", "", "write a heap profile to path after the solve")
+	flags.StringVar(&c.tracePath, "trace", "", "write a runtime execution trace of the solve to path")
+	flags.BoolVar(&c.verbose, "verbose", false, "log timing diagnostics to stderr")
+	if err := flags.Parse(args); err != nil {
+		// The flag set has already reported the problem and printed the usage
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, exitStatus(exitUsage)
+	}
+
+	if c.quiet {
+		if c.format != "text" && c.format != "plain" {
+			return nil, &usageError{fmt.Errorf("-quiet prints bare numbers and cannot be combined with -format %s", c.format)}
+		}
+		// These modes report in labelled lines rather than printing the answers
+		for _, name := range []string{"compare", "check", "watch", "bench-suite", "expect", "expect-distance"} {
+			if flagSet(flags, name) {
+				return nil, &usageError{fmt.Errorf("-quiet prints bare numbers and cannot be combined with -%s", name)}
+			}
+		}
+		c.format = "plain"
+		c.verbose = false
+	}
+	c.timestamped = flagSet(flags, "since")
+	if err := c.validate(flags); err != nil {
+		return nil, &usageError{err}
+	}
+	return c, nil
+}
+
+// validate rejects flag combinations that conflict or that a mode ignores.
+func (c *config) validate(flags *flag.FlagSet) error {
+	if c.base != 10 && (c.leftFile != "" || c.rightFile != "" || c.sortedLeft != "" || c.sortedRight != "") {
+		return fmt.Errorf("-base applies to two-column input and cannot be used with separate column files")
+	}
+	if c.weighted && (c.leftFile != "" || c.rightFile != "") {
+		return fmt.Errorf("-weighted reads a third column and cannot be used with -left and -right")
+	}
+	if c.timestamped && (c.leftFile != "" || c.rightFile != "" || c.sortedLeft != "" || c.sortedRight != "") {
+		return fmt.Errorf("-since reads a third column and cannot be used with separate column files")
+	}
+	if c.groupSum {
+		if c.window > 0 {
+			return fmt.Errorf("-groupsum and -window are separate modes, pick one")
+		}
+		if c.format != "text" && c.format != "plain" {
+			return fmt.Errorf("-groupsum prints text or plain output, not -format %s", c.format)
+		}
+	}
+	if c.window > 0 {
+		if c.windowFreq != "local" && c.windowFreq != "global" {
+			return fmt.Errorf("invalid -window-freq %q: use local or global", c.windowFreq)
+		}
+		if c.format != "text" && c.format != "plain" {
+			return fmt.Errorf("-window prints text or plain output, not -format %s", c.format)
+		}
+		if c.weighted {
+			return fmt.Errorf("-window scores are unweighted and cannot be combined with -weighted")
+		}
+	}
+	if c.dedupLeft && c.weighted {
+		return fmt.Errorf("-dedup-left and -weighted are different scoring variants, pick one")
+	}
+	if c.paranoid && (c.weighted || c.dedupLeft) {
+		return fmt.Errorf("-paranoid cross-checks the plain similarity score and cannot be combined with -weighted or -dedup-left")
+	}
+	if c.explain && c.weighted {
+		return fmt.Errorf("-explain walks through the unweighted score and cannot be combined with -weighted")
+	}
+	if c.dir != "" {
+		for _, name := range []string{"input", "data", "left", "right", "sorted-left", "sorted-right", "check", "compare", "watch", "repeat"} {
+			if flagSet(flags, name) {
+				return fmt.Errorf("-dir cannot be combined with -%s", name)
+			}
+		}
+		if c.format != "text" && c.format != "table" && c.format != "ndjson" {
+			return fmt.Errorf("-dir prints a table or ndjson, not -format %s", c.format)
+		}
+	} else if c.format == "ndjson" && !flagSet(flags, "repeat") {
+		return fmt.Errorf("-format ndjson writes one line per input or iteration and needs -dir or -repeat")
+	}
+	if c.data != "" {
+		// The other input sources and the modes that re-read a file have nothing to read
+		for _, name := range []string{"input", "left", "right", "sorted-left", "sorted-right", "check", "compare", "watch", "repeat"} {
+			if flagSet(flags, name) {
+				return fmt.Errorf("-data cannot be combined with -%s", name)
+			}
+		}
+	}
+	if c.part < 0 || c.part > 2 {
+		return fmt.Errorf("invalid -part %d: use 1 or 2", c.part)
+	}
+	if c.dir == "" && (c.repeat > 1 || c.format == "ndjson") {
+		// Repeated runs time the plain solve, which these flags change or add to
+		for _, name := range []string{"left", "right", "groupsum", "window", "dedup-left", "dense", "paranoid", "swap", "shuffle", "max-memory-mb", "stats", "histogram", "top", "checksum", "explain", "dump-sorted", "remember", "progress"} {
+			if flagSet(flags, name) {
+				return fmt.Errorf("-repeat cannot be combined with -%s", name)
+			}
+		}
+	}
+	if c.benchSuite && c.weighted {
+		return fmt.Errorf("-bench-suite generates two-column input and cannot be combined with -weighted")
+	}
+	if (c.sortedLeft != "" || c.sortedRight != "") && c.part == 2 {
+		return fmt.Errorf("-sorted-left and -sorted-right only compute part 1")
+	}
+	return nil
+}
+
+// parseOptions returns the parse options selected by the flags.
+func (c *config) parseOptions() (day01.ParseOptions, error) {
+	delim, err := parseDelimiter(c.delimiter)
+	if err != nil {
+		return day01.ParseOptions{}, err
+	}
+	commentRune, err := parseComment(c.comment)
+	if err != nil {
+		return day01.ParseOptions{}, err
+	}
+	fieldIndices, err := parseFieldIndices(c.fieldCols)
+	if err != nil {
+		return day01.ParseOptions{}, err
+	}
+	opts := day01.ParseOptions{
+		Strict:       c.strict || c.failFast,
+		Delimiter:    delim,
+		Gzip:         c.gzipInput,
+		NonNegative:  c.nonneg,
+		Comment:      commentRune,
+		Clean:        c.clean,
+		SkipLines:    c.skipLines,
+		HeadLines:    c.headLines,
+		FieldIndices: fieldIndices,
+		Weighted:     c.weighted,
+		Timestamped:  c.timestamped,
+		Since:        c.since,
+		MaxValue:     c.maxValue,
+		SkipSamples:  c.showSkipped,
+		Timeout:      c.timeout,
+		Retries:      c.retries,
+		Base:         c.base,
+		Mmap:         c.mmap,
+		Pad:          c.pad,
+		PadValue:     c.padValue,
+	}
+	if err := opts.Validate(); err != nil {
+		return day01.ParseOptions{}, err
+	}
+	if c.parallel {
+		opts.Workers = c.workers
+	}
+	return opts, nil
+}
+
+// run is the body of the top-level run, returning errors instead of exit codes.
+// It parses the flags and hands off to the selected mode.
+func (a *app) run(args []string) error {
+	c, err := parseConfig(args, a.stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if c.quiet {
+		a.stderr = io.Discard
+	}
+	a.logger = newLogger(a.stderr, c.verbose)
+	if a.color, err = useColor(c.colorMode, a.stdout, c.output); err != nil {
+		return &usageError{err}
+	}
+	opts, err := c.parseOptions()
+	if err != nil {
+		return &usageError{err}
+	}
+
+	switch {
+	case c.generate > 0:
+		return a.writeOutput(c.output, func(w io.Writer) error {
+			return day01.Generate(w, c.generate, c.seed)
+		})
+	case c.benchSuite:
+		return a.runBenchSuite(opts)
+	case c.check:
+		return a.checkMode(c, opts)
+	case c.compare != "":
+		return a.compareMode(c, opts)
+	case c.watch:
+		return a.watch(c.input, opts, c.part)
+	case c.dir != "":
+		return a.dirMode(c, opts)
+	}
+
+	if c.cpuprofile != "" {
+		if err := a.startCPUProfile(c.cpuprofile); err != nil {
+			return err
+		}
+	}
+	if c.tracePath != "" {
+		if err := a.startTrace(c.tracePath); err != nil {
+			return err
+		}
+	}
+	switch {
+	case c.sortedLeft != "" || c.sortedRight != "":
+		return a.sortedMode(c)
+	case c.repeat > 1 || c.format == "ndjson":
+		return a.repeatMode(c, opts)
+	}
+	return a.solveMode(c, opts)
+}
+
+// checkMode validates the input for -check, exiting with exitParse if -strict
+// would fail on it.
+func (a *app) checkMode(c *config, opts day01.ParseOptions) error {
+	clean, err := a.runCheck(c.input, opts)
+	if err != nil {
+		return err
+	}
+	if !clean {
+		return exitStatus(exitParse)
+	}
+	return nil
+}
+
+// compareMode solves -input and -compare, exiting with exitMismatch if their
+// answers differ.
+func (a *app) compareMode(c *config, opts day01.ParseOptions) error {
+	same, err := a.runCompare(c.input, c.compare, opts)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return exitStatus(exitMismatch)
+	}
+	return nil
+}
+
+// dirMode solves every input in -dir and prints a table or one ndjson line per
+// file. Failed files are listed with the others, then the exit status is that of
+// the first failure.
+func (a *app) dirMode(c *config, opts day01.ParseOptions) error {
+	var results []dirResult
+	if err := a.writeOutput(c.output, func(w io.Writer) error {
+		if c.format == "ndjson" {
+			enc := json.NewEncoder(w)
+			var err error
+			results, err = solveDir(c.dir, opts, c.workers, func(r dirResult) error {
+				line := ndjsonLine{File: r.name}
+				if r.err != nil {
+					line.Error = r.err.Error()
+				} else {
+					line = line.withAnswers(r.res.TotalDistance, r.res.SimilarityScore, r.res.Elapsed)
+				}
+				return enc.Encode(line)
+			})
+			return err
+		}
+		var err error
+		if results, err = solveDir(c.dir, opts, c.workers, nil); err != nil {
+			return err
+		}
+		return printDirTable(w, results)
+	}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.err != nil {
+			return exitStatus(exitCode(r.err))
+		}
+	}
+	return nil
+}
+
+// sortedMode streams part 1 from the pre-sorted -sorted-left and -sorted-right files.
+func (a *app) sortedMode(c *config) error {
+	distance, err := a.streamSortedDistance(c.sortedLeft, c.sortedRight)
+	if err != nil {
+		return err
+	}
+	a.stopCPUProfile()
+	return a.writeOutput(c.output, func(w io.Writer) error {
+		return a.printAnswers(w, c.format, 1, &day01.Result{TotalDistance: distance})
+	})
+}
+
+// repeatMode solves the input -repeat times and reports timing statistics. With
+// -format ndjson each iteration's answers are written as soon as it is solved.
+func (a *app) repeatMode(c *config, opts day01.ParseOptions) error {
+	if c.input == "-" {
+		return fmt.Errorf("-repeat needs a file input, stdin cannot be re-read")
+	}
+	if c.format == "ndjson" {
+		return a.writeOutput(c.output, func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			durations, err := timeRepeated(c.input, opts, c.repeat, func(iteration int, res *day01.Result, elapsed time.Duration) error {
+				return enc.Encode(ndjsonLine{Iteration: iteration}.withAnswers(res.TotalDistance, res.SimilarityScore, elapsed))
+			})
+			if err != nil {
+				return err
+			}
+			a.printTimings(durations)
+			return nil
+		})
+	}
+	// Report the answers of the last measured run rather than solving once more
+	var last *day01.Result
+	durations, err := timeRepeated(c.input, opts, c.repeat, func(_ int, res *day01.Result, _ time.Duration) error {
+		last = res
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.printTimings(durations)
+	if c.expectScore.set || c.expectDistance.set {
+		return a.verifyAnswers(last, c.expectDistance, c.expectScore)
+	}
+	return a.writeOutput(c.output, func(w io.Writer) error {
+		return a.printAnswers(w, c.format, c.part, last)
+	})
+}
+
+// solveMode parses the input once and prints both answers, along with whichever
+// diagnostics the flags ask for.
+func (a *app) solveMode(c *config, opts day01.ParseOptions) error {
+	if c.maxMemoryMB > 0 {
+		distance, overBudget, err := a.solveWithinBudget(c.input, opts, c.maxMemoryMB, c.part)
+		if err != nil {
+			return err
+		}
+		if overBudget {
+			return a.writeOutput(c.output, func(w io.Writer) error {
+				return a.printAnswers(w, c.format, 1, &day01.Result{TotalDistance: distance})
+			})
+		}
+	}
+
 	totalStart := time.Now()
+	var err error
 
-	score, err := calculateSimilarityScore("../puzzle_input.txt")
+	// Parse once and share the columns between both parts. The first Ctrl-C stops
+	// the parse with a progress report, and a second one kills the process.
+	ctx, stopInterrupt := interruptContext()
+	var cols *day01.Columns
+	trace.WithRegion(context.Background(), "parse", func() {
+		if c.data != "" {
+			cols, err = a.parseData(c.data, opts)
+		} else if c.leftFile != "" || c.rightFile != "" {
+			cols, err = a.parseSeparate(c.leftFile, c.rightFile, opts)
+		} else if c.showProgress && !c.quiet {
+			opts.Progress = &day01.Progress{}
+			stop := a.startProgress(c.input, opts)
+			cols, err = a.parseInput(ctx, c.input, opts)
+			stop()
+		} else {
+			cols, err = a.parseInput(ctx, c.input, opts)
+		}
+	})
+	stopInterrupt()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if c.failFast {
+			a.printLineContext(err)
+		}
+		return err
+	}
+
+	if c.swap {
+		cols.Swap()
+	}
+	if c.shuffle != 0 {
+		cols.Shuffle(c.shuffle)
 	}
 
-	fmt.Printf("Program completed in %v\n", time.Since(totalStart))
-	fmt.Printf("Similarity Score: %d\n", score)
+	if c.groupSum {
+		sums := day01.GroupSums(cols.Left, cols.Right)
+		return a.writeOutput(c.output, func(w io.Writer) error {
+			return printGroupSums(w, c.format, sums)
+		})
+	}
+
+	if c.window > 0 {
+		// Windows follow input order, so score them before anything sorts the columns
+		windows, err := day01.WindowScores(cols.Left, cols.Right, c.window, c.step, c.windowFreq == "global")
+		if err != nil {
+			return err
+		}
+		if len(windows) == 0 {
+			fmt.Fprintf(a.stderr, "Warning: only %d pairs parsed, fewer than one -window of %d\n", len(cols.Left), c.window)
+		}
+		return a.writeOutput(c.output, func(w io.Writer) error {
+			return printWindows(w, c.format, windows)
+		})
+	}
+
+	if c.checksum {
+		// Checksum the pairs as parsed, before the solve sorts each column on its own
+		fmt.Fprintf(a.stderr, "Checksum: %016x\n", day01.Checksum(cols.Left, cols.Right))
+	}
+	if c.explain {
+		// Explain in input order, before the solve sorts the columns
+		if len(cols.Left) > maxExplainPairs {
+			fmt.Fprintf(a.stderr, "Warning: -explain is limited to %d pairs, %d parsed; skipping the walkthrough\n", maxExplainPairs, len(cols.Left))
+		} else {
+			left := cols.Left
+			if c.dedupLeft {
+				left = day01.DedupLeft(left)
+			}
+			a.printExplanation(day01.ExplainSimilarity(left, day01.Frequencies(cols.Right)))
+		}
+	}
+
+	calcStart := time.Now()
+	var (
+		rightFreq map[int64]int64
+		res       *day01.Result
+	)
+	trace.WithRegion(context.Background(), "calc", func() {
+		if c.dense {
+			var ok bool
+			if res, ok = day01.NewDenseResult(cols); !ok {
+				fmt.Fprintln(a.stderr, "Warning: right values span too wide a range for -dense, using the frequency map")
+			}
+		}
+		if res == nil {
+			rightFreq = frequencies(cols.Right)
+			res = day01.NewResult(cols, rightFreq)
+		}
+		if c.dedupLeft {
+			if rightFreq == nil {
+				rightFreq = frequencies(cols.Right)
+			}
+			res.SimilarityScore = day01.ScoreWithFrequencies(day01.DedupLeft(cols.Left), rightFreq)
+		}
+	})
+	if c.paranoid {
+		if err := crossCheckSimilarity(cols, res.SimilarityScore); err != nil {
+			return err
+		}
+	}
+	res.ParseElapsed = calcStart.Sub(totalStart)
+	res.CalcElapsed = time.Since(calcStart)
+	a.logger.Debug("calculation complete", "elapsed", res.CalcElapsed)
+
+	if res.LinesParsed < c.minLines {
+		fmt.Fprintf(a.stderr, "Warning: only %d lines parsed — did you mean to use the full input?\n", res.LinesParsed)
+	}
+	if c.dumpPrefix != "" {
+		// The solve has just sorted both columns, before -top reuses them
+		if err := a.dumpSorted(c.dumpPrefix, cols); err != nil {
+			return err
+		}
+	}
+	if rightFreq == nil && (c.stats || c.histogram) {
+		rightFreq = day01.Frequencies(cols.Right)
+	}
+	if c.stats {
+		a.printStats(day01.ComputeStats(cols.Left, rightFreq), cols, opts.SkipLines > 0 || opts.HeadLines > 0)
+	}
+	if c.histogram {
+		a.printHistogram(day01.FrequencyHistogram(rightFreq))
+	}
+	if c.top > 0 {
+		a.printTopPairs(day01.TopPairs(cols.Left, cols.Right, c.top))
+	}
+
+	res.Elapsed = time.Since(totalStart)
+	a.logger.Debug("solve complete", "elapsed", res.Elapsed)
+	a.stopCPUProfile()
+	a.stopTrace()
+	if c.memprofile != "" {
+		if err := writeMemProfile(c.memprofile); err != nil {
+			return err
+		}
+	}
+	if c.remember != "" {
+		if err := a.rememberAnswers(c.remember, res); err != nil {
+			return err
+		}
+	}
+	if c.expectScore.set || c.expectDistance.set {
+		return a.verifyAnswers(res, c.expectDistance, c.expectScore)
+	}
+	return a.writeOutput(c.output, func(w io.Writer) error {
+		return a.printAnswers(w, c.format, c.part, res)
+	})
 }
@@ -0,0 +1,1127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"aoc-2024/day-01/go/day01"
+)
+
+// example is the puzzle's worked example: total distance 11, similarity score 31.
+const example = `3   4
+4   3
+2   5
+1   3
+3   9
+3   3
+`
+
+// TestMain lets the test binary stand in for the CLI: re-executed by command, it
+// runs main instead of the tests.
+func TestMain(m *testing.M) {
+	if os.Getenv("DAY01_RUN_MAIN") == "1" {
+		main()
+	}
+	os.Exit(m.Run())
+}
+
+// command returns a command running the CLI with args in a subprocess.
+func command(args ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "DAY01_RUN_MAIN=1")
+	return cmd
+}
+
+// runCLI runs the command line with args and returns its exit code and output.
+func runCLI(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// lines delivers the lines read from r until it is closed.
+func lines(r io.Reader) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			ch <- scanner.Text()
+		}
+	}()
+	return ch
+}
+
+// nextLine returns the next line from ch, failing the test if none arrives in time.
+func nextLine(t *testing.T, ch <-chan string) string {
+	t.Helper()
+
+	select {
+	case line, ok := <-ch:
+		if !ok {
+			t.Fatal("output ended early")
+		}
+		return line
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for output")
+	}
+	return ""
+}
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMultipleInputs(t *testing.T) {
+	dir := t.TempDir()
+	lines := strings.SplitAfter(example, "\n")
+	first := writeFile(t, dir, "a.txt", strings.Join(lines[:3], ""))
+	second := writeFile(t, dir, "b.txt", strings.Join(lines[3:], "")+"oops\n")
+
+	code, stdout, stderr := runCLI(t, "-input", first+","+second, "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's combined answers", stdout)
+	}
+	// The malformed line is reported against the file it came from
+	if !strings.Contains(stderr, second+": Skipped 1 malformed lines") || strings.Contains(stderr, first+": Skipped") {
+		t.Errorf("stderr does not report the skip against %s only:\n%s", second, stderr)
+	}
+}
+
+func TestExitCodeFileNotFound(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := command("-input", filepath.Join(t.TempDir(), "missing.txt"))
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Run error = %v, want an exit status", err)
+	}
+	if code := exitErr.ExitCode(); code != 2 {
+		t.Errorf("exit code %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "input file not found") {
+		t.Errorf("stderr = %q, want the not-found error", stderr.String())
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	input := writeFile(t, dir, "input.txt", example)
+	output := writeFile(t, dir, "answers.txt", "stale content that must be truncated\n")
+
+	code, stdout, stderr := runCLI(t, "-input", input, "-output", output)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q, want the answers in the file only", stdout)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Part 1 (total distance): 11\nPart 2 (similarity score): 31\n"; string(got) != want {
+		t.Errorf("output file = %q, want %q", got, want)
+	}
+}
+
+func TestPart(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "both", want: "Part 1 (total distance): 11\nPart 2 (similarity score): 31\n"},
+		{name: "part 1", args: []string{"-part", "1"}, want: "Part 1 (total distance): 11\n"},
+		{name: "part 2", args: []string{"-part", "2"}, want: "Part 2 (similarity score): 31\n"},
+		{name: "part 1 plain", args: []string{"-part", "1", "-format", "plain"}, want: "11\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, append([]string{"-input", input}, tt.args...)...)
+			if code != 0 {
+				t.Fatalf("exit code %d, stderr: %s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+
+	if code, _, stderr := runCLI(t, "-input", input, "-part", "3"); code == 0 || !strings.Contains(stderr, "invalid -part 3") {
+		t.Errorf("-part 3 exited %d with stderr %q, want it rejected", code, stderr)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	input := writeFile(t, dir, "input.txt", example)
+	// The same pairs in another order have the same answers
+	reformatted := writeFile(t, dir, "reformatted.txt", "3 3\n3 9\n1 3\n2 5\n4 3\n3 4\n")
+	changed := writeFile(t, dir, "changed.txt", strings.Replace(example, "3   3", "3   4", 1))
+
+	code, stdout, stderr := runCLI(t, "-input", input, "-compare", reformatted)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	want := "Part 1 (total distance): 11 vs 11 (match)\nPart 2 (similarity score): 31 vs 31 (match)\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+
+	code, stdout, _ = runCLI(t, "-input", input, "-compare", changed)
+	if code != 4 {
+		t.Errorf("exit code %d for differing answers, want 4", code)
+	}
+	if !strings.Contains(stdout, "Part 2 (similarity score): 31 vs 26 (differ)") {
+		t.Errorf("stdout = %q, want the similarity scores reported as different", stdout)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{name: "unknown flag", args: []string{"-nope"}, code: 64, stderr: "flag provided but not defined: -nope"},
+		{name: "bad flag value", args: []string{"-repeat", "many"}, code: 64, stderr: `invalid value "many" for flag -repeat`},
+		{name: "invalid combination", args: []string{"-input", input, "-quiet", "-format", "json"}, code: 64, stderr: "Error: -quiet prints bare numbers"},
+		{name: "invalid parse option", args: []string{"-input", input, "-base", "7"}, code: 64, stderr: "Error: unsupported base 7"},
+		{name: "file not found", args: []string{"-input", filepath.Join(t.TempDir(), "missing.txt")}, code: 2, stderr: "Error: input file not found"},
+		{name: "help", args: []string{"-help"}, code: 0, stderr: "Usage of day01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tt.args...)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want nothing", stdout)
+			}
+		})
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+
+	code, stdout, stderr := runCLI(t, "-input", input, "-repeat", "3", "-verbose")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if want := "Part 1 (total distance): 11\nPart 2 (similarity score): 31\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if !strings.Contains(stderr, "Runs: 3,") {
+		t.Errorf("stderr = %q, want timings for 3 runs", stderr)
+	}
+	// The answers come from the last timed run, not from a separate solve
+	if strings.Contains(stderr, "parse complete") {
+		t.Errorf("stderr = %q, want no solve beyond the timed runs", stderr)
+	}
+
+	if code, stdout, _ := runCLI(t, "-input", input, "-repeat", "2", "-expect", "30"); code != 4 || !strings.Contains(stdout, "MISMATCH") {
+		t.Errorf("-expect 30 exited %d with stdout %q, want a mismatch", code, stdout)
+	}
+	if code, _, stderr := runCLI(t, "-input", input, "-repeat", "2", "-stats"); code != 64 {
+		t.Errorf("-repeat with -stats exited %d, want 64; stderr: %s", code, stderr)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent to a process on Windows")
+	}
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+
+	cmd := command("-input", input, "-watch")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	out := lines(stdout)
+
+	if line := nextLine(t, out); !strings.HasSuffix(line, "] Part 1 (total distance): 11, Part 2 (similarity score): 31") {
+		t.Errorf("first line = %q, want the example's answers", line)
+	}
+	if err := os.WriteFile(input, []byte(example+"5   5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if line := nextLine(t, out); !strings.HasSuffix(line, "] Part 1 (total distance): 11, Part 2 (similarity score): 41") {
+		t.Errorf("line after the edit = %q, want the new answers", line)
+	}
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("watch exited with %v after Ctrl-C, want a clean exit", err)
+	}
+}
+
+func TestStatsSkipCounts(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "3 4\n\n4 3\n   \t\n2 5 8\n1 3\nx y\n3 9\n3 3\n")
+	code, _, stderr := runCLI(t, "-input", input, "-stats")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if want := "Skipped lines: 2 blank, 1 wrong field count, 1 non-integer, 0 out of range\n"; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q, want it to contain %q", stderr, want)
+	}
+}
+
+func TestSeparateInputs(t *testing.T) {
+	dir := t.TempDir()
+	left := writeFile(t, dir, "left.txt", "3\n4\n2\n1\n3\n3\n")
+	right := writeFile(t, dir, "right.txt", "4\n3\n5\n3\n9\n3\n")
+	code, stdout, stderr := runCLI(t, "-left", left, "-right", right, "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the two-column example's answers", stdout)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	dir := t.TempDir()
+	// A malformed line and a short input would both print warnings without -quiet
+	input := writeFile(t, dir, "input.txt", example+"oops\n")
+	other := writeFile(t, dir, "other.txt", example)
+
+	code, stdout, stderr := runCLI(t, "-input", input, "-quiet", "-verbose")
+	if code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	if stdout != "11\n31\n" || stderr != "" {
+		t.Errorf("stdout = %q, stderr = %q, want only the bare answers", stdout, stderr)
+	}
+	if _, stdout, _ := runCLI(t, "-input", input, "-quiet", "-part", "2"); stdout != "31\n" {
+		t.Errorf("-part 2 stdout = %q, want %q", stdout, "31\n")
+	}
+
+	for _, args := range [][]string{{"-compare", other}, {"-check"}, {"-expect", "31"}, {"-format", "json"}} {
+		code, stdout, stderr := runCLI(t, append([]string{"-input", input, "-quiet"}, args...)...)
+		if code != 64 || stdout != "" || !strings.Contains(stderr, "-quiet prints bare numbers") {
+			t.Errorf("-quiet %v exited %d with stdout %q, stderr %q; want it rejected", args, code, stdout, stderr)
+		}
+	}
+}
+
+func TestExpect(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+	}{
+		{name: "score matches", args: []string{"-expect", "31"}, stdout: "OK\n"},
+		{name: "both match", args: []string{"-expect", "31", "-expect-distance", "11"}, stdout: "OK\n"},
+		{name: "score differs", args: []string{"-expect", "30"}, code: 4, stdout: "MISMATCH: part 2 (similarity score) got 31 want 30\n"},
+		{
+			name:   "both differ",
+			args:   []string{"-expect", "30", "-expect-distance", "12"},
+			code:   4,
+			stdout: "MISMATCH: part 1 (total distance) got 11 want 12\nMISMATCH: part 2 (similarity score) got 31 want 30\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, append([]string{"-input", input}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d; stderr: %s", code, tt.code, stderr)
+			}
+			if stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.stdout)
+			}
+		})
+	}
+
+	if code, _, stderr := runCLI(t, "-input", input, "-expect", "lots"); code != 64 || !strings.Contains(stderr, "expected an integer answer") {
+		t.Errorf("-expect lots exited %d with stderr %q, want it rejected", code, stderr)
+	}
+}
+
+func TestLineWindowStats(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "100 1\n200 2\n"+example+"70 7\n80 8\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-skip", "2", "-head", "6", "-stats", "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's answers", stdout)
+	}
+	if !strings.Contains(stderr, "Lines in window: 6, after skipping 2\n") {
+		t.Errorf("stderr = %q, want the window size reported", stderr)
+	}
+}
+
+func TestMinLinesWarning(t *testing.T) {
+	dir := t.TempDir()
+	small := writeFile(t, dir, "small.txt", strings.Repeat("1 2\n", 5))
+	large := writeFile(t, dir, "large.txt", strings.Repeat("1 2\n", 50))
+	const warning = "Warning: only 5 lines parsed — did you mean to use the full input?\n"
+
+	if _, _, stderr := runCLI(t, "-input", small); !strings.Contains(stderr, warning) {
+		t.Errorf("stderr for 5 lines = %q, want the warning", stderr)
+	}
+	if _, _, stderr := runCLI(t, "-input", large); strings.Contains(stderr, "Warning") {
+		t.Errorf("stderr for 50 lines = %q, want no warning", stderr)
+	}
+	if _, _, stderr := runCLI(t, "-input", small, "-min-lines", "5"); strings.Contains(stderr, "Warning") {
+		t.Errorf("stderr with -min-lines 5 = %q, want no warning", stderr)
+	}
+	if _, _, stderr := runCLI(t, "-input", small, "-quiet"); stderr != "" {
+		t.Errorf("stderr with -quiet = %q, want nothing", stderr)
+	}
+}
+
+func TestCols(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "a 3 x 4\nb 4 y 3\nc 2 z 5\nd 1 w 3\ne 3 v 9\nf 3 u 3\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-cols", "1,3", "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's answers", stdout)
+	}
+	if code, _, _ := runCLI(t, "-input", input, "-cols", "1"); code != 64 {
+		t.Errorf("-cols 1 exited %d, want 64", code)
+	}
+}
+
+func TestProgressStops(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	before := runtime.NumGoroutine()
+
+	var stderr bytes.Buffer
+	a := &app{stdout: io.Discard, stderr: &stderr}
+	opts := day01.ParseOptions{Progress: &day01.Progress{}}
+	stop := a.startProgress(input, opts)
+	if _, err := day01.ParseColumns(input, opts); err != nil {
+		t.Fatal(err)
+	}
+	stop()
+
+	// stop waits for the reporting goroutine, so none is left behind
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines after stop, %d before start", after, before)
+	}
+	// A parse shorter than the interval finishes before the first report
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want no report", stderr.String())
+	}
+}
+
+func TestFormatKV(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "# ids\n"+example+"oops\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-comment", "#", "-format", "kv")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	const want = "distance=11\nlines_commented=1\nlines_parsed=6\nlines_skipped=1\nsimilarity=31\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestInputEnv(t *testing.T) {
+	dir := t.TempDir()
+	fromEnv := writeFile(t, dir, "env.txt", example)
+	fromFlag := writeFile(t, dir, "flag.txt", "1 1\n")
+	t.Setenv("AOC_INPUT", fromEnv)
+
+	if _, stdout, stderr := runCLI(t, "-format", "plain"); stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the answers for $AOC_INPUT; stderr: %s", stdout, stderr)
+	}
+	// -input takes precedence over the environment
+	if _, stdout, stderr := runCLI(t, "-input", fromFlag, "-format", "plain"); stdout != "0\n1\n" {
+		t.Errorf("stdout = %q, want the answers for -input; stderr: %s", stdout, stderr)
+	}
+}
+
+func TestStdinPipe(t *testing.T) {
+	// The subprocess reads its stdin from a pipe, which can be read only once
+	var stdout bytes.Buffer
+	cmd := command("-input", "-", "-format", "plain")
+	cmd.Stdin = strings.NewReader(example)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if stdout.String() != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's answers", stdout.String())
+	}
+}
+
+func TestTraceFile(t *testing.T) {
+	dir := t.TempDir()
+	input := writeFile(t, dir, "input.txt", example)
+	tracePath := filepath.Join(dir, "solve.trace")
+
+	code, _, stderr := runCLI(t, "-input", input, "-trace", tracePath)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	data, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Execution traces start with a "go 1.N trace" header
+	if !bytes.HasPrefix(data, []byte("go 1.")) || len(data) < 100 {
+		t.Errorf("trace file holds %d bytes starting %q, want a complete trace", len(data), data[:min(len(data), 16)])
+	}
+}
+
+func TestData(t *testing.T) {
+	// The flag value holds the escapes literally, as typed in a shell
+	data := `3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n`
+	code, stdout, stderr := runCLI(t, "-data", data, "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's answers", stdout)
+	}
+	tabs := `3\t4\n4\t3\n2\t5\n1\t3\n3\t9\n3\t3`
+	if _, stdout, stderr := runCLI(t, "-data", tabs, "-format", "plain"); stdout != "11\n31\n" {
+		t.Errorf("tab-separated stdout = %q, want the example's answers; stderr: %s", stdout, stderr)
+	}
+
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	if code, _, stderr := runCLI(t, "-data", data, "-input", input); code != 64 || !strings.Contains(stderr, "-data cannot be combined with -input") {
+		t.Errorf("-data with -input exited %d with stderr %q, want it rejected", code, stderr)
+	}
+}
+
+func TestStatsOrder(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "1 9\n2 3\n3 4\n")
+	_, _, stderr := runCLI(t, "-input", input, "-stats")
+	if want := "Input order: left already sorted, right unsorted\n"; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q, want it to contain %q", stderr, want)
+	}
+}
+
+func TestFormatTable(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example+"oops\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-format", "table")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	rows := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	want := [][]string{
+		{"Total", "distance", "11"},
+		{"Similarity", "score", "31"},
+		{"Lines", "parsed", "6"},
+		{"Lines", "skipped", "1"},
+	}
+	if len(rows) != len(want)+1 {
+		t.Fatalf("table has %d rows, want %d:\n%s", len(rows), len(want)+1, stdout)
+	}
+	for i, fields := range want {
+		if got := strings.Fields(rows[i]); !slices.Equal(got, fields) {
+			t.Errorf("row %d = %q, want %q", i+1, got, fields)
+		}
+	}
+	if !strings.HasPrefix(rows[len(want)], "Elapsed ") {
+		t.Errorf("last row = %q, want the elapsed time", rows[len(want)])
+	}
+	// Values line up in one column
+	column := strings.Index(rows[0], "11")
+	if strings.Index(rows[1], "31") != column || strings.Index(rows[2], "6") != column {
+		t.Errorf("values are not aligned:\n%s", stdout)
+	}
+}
+
+func TestRemember(t *testing.T) {
+	dir := t.TempDir()
+	input := writeFile(t, dir, "input.txt", example)
+	memory := filepath.Join(dir, "answers.json")
+
+	_, _, stderr := runCLI(t, "-input", input, "-remember", memory)
+	if !strings.Contains(stderr, "Recording answers in "+memory) {
+		t.Errorf("first run stderr = %q, want the answers recorded", stderr)
+	}
+	data, err := os.ReadFile(memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"distance":11,"similarity":31}` + "\n"; string(data) != want {
+		t.Errorf("recorded %q, want %q", data, want)
+	}
+
+	// A second run, after an edit, reports each answer against the first
+	writeFile(t, dir, "input.txt", example+"5   5\n")
+	_, _, stderr = runCLI(t, "-input", input, "-remember", memory)
+	for _, want := range []string{"distance: 11 -> 11, unchanged\n", "similarity: 31 -> 41, changed\n"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("second run stderr = %q, want it to contain %q", stderr, want)
+		}
+	}
+
+	writeFile(t, dir, "answers.json", "not json")
+	if code, _, stderr := runCLI(t, "-input", input, "-remember", memory); code == 0 || !strings.Contains(stderr, "error reading remembered answers") {
+		t.Errorf("corrupt memory exited %d with stderr %q, want an error", code, stderr)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	for _, seed := range []string{"1", "2", "-7"} {
+		if _, stdout, stderr := runCLI(t, "-input", input, "-shuffle", seed, "-format", "plain"); stdout != "11\n31\n" {
+			t.Errorf("-shuffle %s stdout = %q, want the example's answers; stderr: %s", seed, stdout, stderr)
+		}
+	}
+}
+
+func TestGroupSum(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "3 10\n1 5\n3 -4\n-2 7\n1 0\n3 1\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-groupsum", "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if want := "-2 7\n1 5\n3 7\n"; stdout != want {
+		t.Errorf("stdout = %q, want the totals by ascending key %q", stdout, want)
+	}
+}
+
+func TestColor(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	code, stdout, stderr := runCLI(t, "-input", input, "-color", "never")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if strings.Contains(stdout, "\x1b[") {
+		t.Errorf("-color never wrote escape codes: %q", stdout)
+	}
+	if !strings.Contains(stdout, "11") || !strings.Contains(stdout, "31") {
+		t.Errorf("stdout = %q, want both answers", stdout)
+	}
+
+	if _, stdout, _ = runCLI(t, "-input", input, "-color", "always"); !strings.Contains(stdout, "\x1b[") {
+		t.Errorf("-color always wrote no escape codes: %q", stdout)
+	}
+	// Captured output is not a terminal, so auto stays plain
+	if _, stdout, _ = runCLI(t, "-input", input); strings.Contains(stdout, "\x1b[") {
+		t.Errorf("-color auto wrote escape codes to a buffer: %q", stdout)
+	}
+	if code, _, _ := runCLI(t, "-input", input, "-color", "sometimes"); code != exitUsage {
+		t.Errorf("-color sometimes exit code = %d, want %d", code, exitUsage)
+	}
+}
+
+func TestShowSkipped(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "3 4\nx\n4 3\ny\nz\n")
+	code, _, stderr := runCLI(t, "-input", input, "-show-skipped", "2")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "Skipped 3 malformed lines") {
+		t.Errorf("stderr = %q, want the total of 3 skipped lines", stderr)
+	}
+	if !strings.Contains(stderr, "  line 2: ") || !strings.Contains(stderr, "  line 4: ") {
+		t.Errorf("stderr = %q, want lines 2 and 4 listed", stderr)
+	}
+	if strings.Contains(stderr, "  line 5: ") {
+		t.Errorf("stderr = %q, listed line 5 past the cap of 2", stderr)
+	}
+}
+
+// recordHandler keeps every record logged through it.
+type recordHandler struct {
+	level   slog.Level
+	records *[]slog.Record
+}
+
+func (h recordHandler) Enabled(_ context.Context, level slog.Level) bool { return level >= h.level }
+
+func (h recordHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h recordHandler) WithGroup(string) slog.Handler { return h }
+
+func TestLogger(t *testing.T) {
+	for _, verbose := range []bool{false, true} {
+		logger := newLogger(io.Discard, verbose)
+		if got := logger.Enabled(context.Background(), slog.LevelDebug); got != verbose {
+			t.Errorf("newLogger(verbose %v) enables debug = %v", verbose, got)
+		}
+		if !logger.Enabled(context.Background(), slog.LevelInfo) {
+			t.Errorf("newLogger(verbose %v) disables info", verbose)
+		}
+	}
+
+	// The timings are debug records carrying an elapsed duration
+	var records []slog.Record
+	a := &app{stdout: io.Discard, stderr: io.Discard, logger: slog.New(recordHandler{level: slog.LevelDebug, records: &records})}
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	if _, err := a.parseInput(context.Background(), input, day01.ParseOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, r := range records {
+		messages = append(messages, r.Message)
+		if r.Level != slog.LevelDebug {
+			t.Errorf("record %q logged at %v, want debug", r.Message, r.Level)
+		}
+		var elapsed bool
+		r.Attrs(func(attr slog.Attr) bool {
+			elapsed = elapsed || attr.Key == "elapsed" && attr.Value.Kind() == slog.KindDuration
+			return true
+		})
+		if !elapsed {
+			t.Errorf("record %q has no elapsed duration", r.Message)
+		}
+	}
+	if !slices.Contains(messages, "parse complete") {
+		t.Errorf("records = %q, want parse complete", messages)
+	}
+
+	// Without -verbose the text handler drops them
+	_, _, stderr := runCLI(t, "-input", input)
+	if strings.Contains(stderr, "level=DEBUG") {
+		t.Errorf("stderr = %q, want no debug records without -verbose", stderr)
+	}
+	_, _, stderr = runCLI(t, "-input", input, "-verbose")
+	for _, msg := range []string{`level=DEBUG msg="parse complete"`, `level=DEBUG msg="solve complete"`} {
+		if !strings.Contains(stderr, msg) {
+			t.Errorf("stderr = %q, want %s with -verbose", stderr, msg)
+		}
+	}
+}
+
+func TestSwapFlag(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "1 2 10\n2 3 1\n")
+	_, plain, stderr := runCLI(t, "-input", input, "-weighted", "-part", "2", "-format", "plain")
+	_, swapped, _ := runCLI(t, "-input", input, "-weighted", "-part", "2", "-format", "plain", "-swap")
+	if plain != "2\n" || swapped != "20\n" {
+		t.Errorf("weighted scores = %q, %q, want 2 as read and 20 swapped; stderr: %s", plain, swapped, stderr)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	code, _, stderr := runCLI(t, "-input", input, "-explain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	want := "  3 × 3 = 9 (total 9)\n" +
+		"  4 × 1 = 4 (total 13)\n" +
+		"  2 × 0 = 0 (total 13)\n" +
+		"  1 × 0 = 0 (total 13)\n" +
+		"  3 × 3 = 9 (total 22)\n" +
+		"  3 × 3 = 9 (total 31)\n"
+	if !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q, want the example's breakdown %q", stderr, want)
+	}
+
+	big := writeFile(t, t.TempDir(), "big.txt", strings.Repeat("1 1\n", maxExplainPairs+1))
+	_, _, stderr = runCLI(t, "-input", big, "-explain")
+	if !strings.Contains(stderr, "-explain is limited to") || strings.Contains(stderr, " × ") {
+		t.Errorf("stderr = %q, want the walkthrough skipped with a warning", stderr)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent to a process on Windows")
+	}
+	cmd := command("-input", "-")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	kill := time.AfterFunc(10*time.Second, func() { cmd.Process.Kill() })
+	defer kill.Stop()
+
+	// A chunk larger than the pipe buffer is only written once the parse is
+	// reading, by which time the interrupt handler is installed
+	chunk := bytes.Repeat([]byte("3 4\n4 3\n"), 1<<15)
+	if _, err := stdin.Write(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	// Keep the input flowing until the parse reaches its next cancellation check
+	for {
+		if _, err := stdin.Write(chunk); err != nil {
+			break
+		}
+	}
+	stdin.Close()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitInterrupted {
+		t.Fatalf("Wait = %v, want exit code %d; stderr: %s", err, exitInterrupted, stderr.String())
+	}
+	out := stderr.String()
+	if !strings.Contains(out, "Interrupted after ") || !strings.Contains(out, "2 distinct right values counted so far") {
+		t.Errorf("stderr = %q, want the partial summary", out)
+	}
+	if strings.Contains(out, "Interrupted after 0 lines") {
+		t.Errorf("stderr = %q, want the lines read before the interrupt counted", out)
+	}
+}
+
+func TestPadFlag(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "3 4\n4 3\n2 x\n5 y\n")
+	if code, _, _ := runCLI(t, "-input", input); code != exitParse {
+		t.Errorf("unbalanced input without -pad exit code = %d, want %d", code, exitParse)
+	}
+	code, stdout, stderr := runCLI(t, "-input", input, "-pad", "-stats", "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "7\n7\n" {
+		t.Errorf("stdout = %q, want the answers with two zeros padded", stdout)
+	}
+	if !strings.Contains(stderr, "Padded values: 2\n") {
+		t.Errorf("stderr = %q, want the padded count", stderr)
+	}
+}
+
+func TestHeaderReport(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "left right\n"+example+"left right\n")
+	code, stdout, stderr := runCLI(t, "-input", input, "-format", "plain")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if stdout != "11\n31\n" {
+		t.Errorf("stdout = %q, want the example's answers", stdout)
+	}
+	if n := strings.Count(stderr, "Detected and skipped an apparent header row"); n != 1 {
+		t.Errorf("stderr = %q, want the header reported once", stderr)
+	}
+	if !strings.Contains(stderr, "Skipped 1 malformed lines") {
+		t.Errorf("stderr = %q, want the repeated row skipped as malformed", stderr)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "mine.txt", example)
+	writeFile(t, dir, "friend.txt", "1 2\n2 1\n5 5\n")
+	writeFile(t, dir, "broken.txt", "1\n2\n3\n")
+	writeFile(t, dir, "notes.md", "not an input\n")
+
+	code, stdout, stderr := runCLI(t, "-dir", dir, "-workers", "2")
+	// The broken file fails the run without stopping the others
+	if code != exitParse {
+		t.Errorf("exit code %d, want %d; stderr: %s", code, exitParse, stderr)
+	}
+	rows := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(rows) != 4 {
+		t.Fatalf("stdout = %q, want a header and three rows", stdout)
+	}
+	for i, want := range [][]string{
+		{"File", "Distance", "Similarity", "Elapsed"},
+		{"broken.txt", "error:"},
+		{"friend.txt", "0", "8"},
+		{"mine.txt", "11", "31"},
+	} {
+		fields := strings.Fields(rows[i])
+		if len(fields) < len(want) || !slices.Equal(fields[:len(want)], want) {
+			t.Errorf("row %d = %q, want it to start with %q", i, rows[i], want)
+		}
+	}
+}
+
+// readValues reads a file of one integer per line.
+func readValues(t *testing.T, path string) []int64 {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []int64
+	for _, line := range strings.Fields(string(data)) {
+		v, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestDumpSorted(t *testing.T) {
+	dir := t.TempDir()
+	input := writeFile(t, dir, "input.txt", "30 -4\n4 3\n-2 5\n1 3\n3 90\n3 3\nx y\n")
+	prefix := filepath.Join(dir, "dump")
+	code, _, stderr := runCLI(t, "-input", input, "-dump-sorted", prefix)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	for side, original := range map[string][]int64{
+		"left":  {30, 4, -2, 1, 3, 3},
+		"right": {-4, 3, 5, 3, 90, 3},
+	} {
+		got := readValues(t, prefix+"."+side+".txt")
+		if !slices.IsSorted(got) {
+			t.Errorf("%s dump %v is not sorted", side, got)
+		}
+		// Every parsed value is there, the same number of times
+		slices.Sort(original)
+		if !slices.Equal(got, original) {
+			t.Errorf("%s dump = %v, want %v", side, got, original)
+		}
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", "3 4\n4\t3x\n2 5\n")
+	code, _, stderr := runCLI(t, "-input", input, "-fail-fast")
+	if code != exitParse {
+		t.Fatalf("exit code %d, want %d; stderr: %s", code, exitParse, stderr)
+	}
+	// The caret keeps the tab so it sits under 3x however tabs are shown
+	if want := "line 2: 4\t3x\n         \t^\n"; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q, want the line and caret %q", stderr, want)
+	}
+	if !strings.Contains(stderr, "line 2") || !strings.Contains(stderr, "3x") {
+		t.Errorf("stderr = %q, want the error to name line 2 and the bad token", stderr)
+	}
+
+	// Plain -strict fails the same way without the caret
+	_, _, stderr = runCLI(t, "-input", input, "-strict")
+	if strings.Contains(stderr, "^") {
+		t.Errorf("-strict stderr = %q, want no caret", stderr)
+	}
+}
+
+func TestBenchSuite(t *testing.T) {
+	code, stdout, stderr := runCLI(t, "-bench-suite")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	rows := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(rows) != len(benchSuiteSizes) {
+		t.Fatalf("stdout = %q, want one line per size", stdout)
+	}
+	for i, row := range rows {
+		fields := strings.Fields(row)
+		if len(fields) != 5 || fields[0] != strconv.Itoa(benchSuiteSizes[i]) || fields[1] != "lines" || fields[4] != "lines/s" {
+			t.Errorf("row %d = %q, want %d lines with an elapsed time and throughput", i, row, benchSuiteSizes[i])
+			continue
+		}
+		if _, err := time.ParseDuration(fields[2]); err != nil {
+			t.Errorf("row %d elapsed %q: %v", i, fields[2], err)
+		}
+		if rate, err := strconv.ParseFloat(fields[3], 64); err != nil || rate <= 0 {
+			t.Errorf("row %d throughput %q is not a positive rate", i, fields[3])
+		}
+	}
+}
+
+func TestDedupLeftFlag(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	_, full, _ := runCLI(t, "-input", input, "-part", "2", "-format", "plain")
+	_, deduped, stderr := runCLI(t, "-input", input, "-part", "2", "-format", "plain", "-dedup-left")
+	if full != "31\n" || deduped != "13\n" {
+		t.Errorf("scores = %q, %q, want 31 and 13 with -dedup-left; stderr: %s", full, deduped, stderr)
+	}
+}
+
+func TestParanoid(t *testing.T) {
+	input := writeFile(t, t.TempDir(), "input.txt", example)
+	if code, stdout, stderr := runCLI(t, "-input", input, "-paranoid", "-format", "plain"); code != 0 || stdout != "11\n31\n" {
+		t.Fatalf("exit code %d, stdout %q, stderr: %s", code, stdout, stderr)
+	}
+
+	// A frequency map that loses a count gives a wrong score the check must catch
+	t.Cleanup(func() { frequencies = day01.Frequencies })
+	frequencies = func(right []int64) map[int64]int64 {
+		freq := day01.Frequencies(right)
+		freq[3]--
+		return freq
+	}
+	code, stdout, stderr := runCLI(t, "-input", input, "-paranoid")
+	if code != exitInternal {
+		t.Errorf("exit code %d, want %d", code, exitInternal)
+	}
+	if want := "paranoid check failed: similarity score 22 from the frequency map, 31 from the sorted merge"; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q, want %q", stderr, want)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q, want no answers printed", stdout)
+	}
+
+	// Without -paranoid the wrong score goes unnoticed
+	if _, stdout, _ := runCLI(t, "-input", input, "-part", "2", "-format", "plain"); stdout != "22\n" {
+		t.Errorf("stdout = %q, want the faulty map's 22", stdout)
+	}
+}
+
+func TestMaxMemory(t *testing.T) {
+	var buf bytes.Buffer
+	if err := day01.Generate(&buf, 200_000, 5); err != nil {
+		t.Fatal(err)
+	}
+	input := writeFile(t, t.TempDir(), "input.txt", buf.String())
+	res, err := day01.SolveFile(input, day01.ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Within budget the usual solve prints both answers
+	code, stdout, stderr := runCLI(t, "-input", input, "-max-memory-mb", "1024", "-format", "plain")
+	if want := fmt.Sprintf("%d\n%d\n", res.TotalDistance, res.SimilarityScore); code != 0 || stdout != want {
+		t.Errorf("within budget: exit code %d, stdout %q, want %q; stderr: %s", code, stdout, want, stderr)
+	}
+
+	// Over budget only the distance is computed, with an external sort
+	code, stdout, stderr = runCLI(t, "-input", input, "-max-memory-mb", "1", "-format", "plain")
+	if want := fmt.Sprintf("%d\n", res.TotalDistance); code != 0 || stdout != want {
+		t.Errorf("over budget: exit code %d, stdout %q, want %q; stderr: %s", code, stdout, want, stderr)
+	}
+	if !strings.Contains(stderr, "computing only the total distance with an external sort") {
+		t.Errorf("over budget stderr = %q, want the fallback warning", stderr)
+	}
+
+	// Part 2 cannot fall back, so it is refused
+	code, stdout, stderr = runCLI(t, "-input", input, "-max-memory-mb", "1", "-part", "2")
+	if code == 0 || stdout != "" || !strings.Contains(stderr, "the similarity score needs about") {
+		t.Errorf("over budget part 2: exit code %d, stdout %q, stderr %q, want a refusal", code, stdout, stderr)
+	}
+}
+
+func TestSinceFlag(t *testing.T) {
+	dir := t.TempDir()
+	stamped := writeFile(t, dir, "stamped.txt", "3 4 100\n4 3 200\n3 3 250\n1 3 300\n")
+	code, stdout, stderr := runCLI(t, "-input", stamped, "-since", "200", "-format", "plain")
+	if code != 0 || stdout != "3\n9\n" {
+		t.Errorf("exit code %d, stdout %q, want the recent pairs' 3 and 9; stderr: %s", code, stdout, stderr)
+	}
+
+	plain := writeFile(t, dir, "plain.txt", example)
+	code, _, stderr = runCLI(t, "-input", plain, "-since", "200")
+	if code != exitParse || !strings.Contains(stderr, "timestamp") {
+		t.Errorf("exit code %d, stderr %q, want a parse error naming the missing timestamp", code, stderr)
+	}
+}
+
+// decodeNDJSON decodes each line of out as a JSON object.
+func decodeNDJSON(t *testing.T, out string) []map[string]any {
+	t.Helper()
+
+	var objects []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", line, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+// keys returns the sorted keys of obj.
+func keys(obj map[string]any) []string {
+	var names []string
+	for name := range obj {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "b.txt", example)
+	writeFile(t, dir, "a.txt", "1\n2\n")
+	writeFile(t, dir, "c.txt", "5 5\n")
+
+	code, stdout, stderr := runCLI(t, "-dir", dir, "-format", "ndjson")
+	if code != exitParse {
+		t.Errorf("exit code %d, want %d for the broken input; stderr: %s", code, exitParse, stderr)
+	}
+	lines := decodeNDJSON(t, stdout)
+	if len(lines) != 3 {
+		t.Fatalf("stdout = %q, want one line per input", stdout)
+	}
+	// A failed input has only its name and error
+	if got := keys(lines[0]); lines[0]["file"] != "a.txt" || !slices.Equal(got, []string{"error", "file"}) {
+		t.Errorf("line 1 = %v, want only file and error for a.txt", lines[0])
+	}
+	answers := []string{"distance", "elapsed_ns", "file", "similarity"}
+	for i, want := range []struct {
+		file                 string
+		distance, similarity float64
+	}{{"b.txt", 11, 31}, {"c.txt", 0, 5}} {
+		line := lines[i+1]
+		if !slices.Equal(keys(line), answers) || line["file"] != want.file || line["distance"] != want.distance || line["similarity"] != want.similarity {
+			t.Errorf("line %d = %v, want %s with answers %v and %v", i+2, line, want.file, want.distance, want.similarity)
+		}
+	}
+
+	input := filepath.Join(dir, "b.txt")
+	code, stdout, stderr = runCLI(t, "-input", input, "-repeat", "3", "-format", "ndjson")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	lines = decodeNDJSON(t, stdout)
+	if len(lines) != 3 {
+		t.Fatalf("stdout = %q, want one line per iteration", stdout)
+	}
+	for i, line := range lines {
+		if !slices.Equal(keys(line), []string{"distance", "elapsed_ns", "iteration", "similarity"}) ||
+			line["iteration"] != float64(i+1) || line["distance"] != 11.0 || line["similarity"] != 31.0 {
+			t.Errorf("line %d = %v, want iteration %d with the example's answers", i+1, line, i+1)
+		}
+	}
+}
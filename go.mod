@@ -0,0 +1,3 @@
+module aoc-2024
+
+go 1.23
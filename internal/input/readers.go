@@ -0,0 +1,115 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// TwoColumns reads lines of two whitespace-separated integers into a left and a
+// right column. Blank lines are skipped; any other line without exactly two
+// integers is an error naming the line.
+// Time Complexity: O(n) where n is the number of lines
+// Space Complexity: O(n) for both columns
+func TwoColumns(r io.Reader) ([]int64, []int64, error) {
+	var left, right []int64
+	fields := make([][]byte, 0, 4)
+
+	scanner := NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Bytes()
+		if line == 1 {
+			text = TrimBOM(text)
+		}
+		fields = AppendFields(fields[:0], text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("line %d: expected 2 fields, got %d", line, len(fields))
+		}
+
+		leftNum, ok := ParseInt(fields[0])
+		if !ok {
+			return nil, nil, fmt.Errorf("line %d: invalid number: %q", line, fields[0])
+		}
+		rightNum, ok := ParseInt(fields[1])
+		if !ok {
+			return nil, nil, fmt.Errorf("line %d: invalid number: %q", line, fields[1])
+		}
+		left = append(left, leftNum)
+		right = append(right, rightNum)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, ScanError(err, line+1)
+	}
+	return left, right, nil
+}
+
+// SingleColumn reads one integer per line. Blank lines are skipped and surrounding
+// whitespace is ignored.
+// Time Complexity: O(n) where n is the number of lines
+// Space Complexity: O(n) for the values
+func SingleColumn(r io.Reader) ([]int64, error) {
+	var values []int64
+
+	scanner := NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Bytes()
+		if line == 1 {
+			text = TrimBOM(text)
+		}
+		field := bytes.TrimSpace(text)
+		if len(field) == 0 {
+			continue
+		}
+
+		value, ok := ParseInt(field)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid number: %q", line, field)
+		}
+		values = append(values, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, ScanError(err, line+1)
+	}
+	return values, nil
+}
+
+// Grid reads a rectangular grid of bytes, one row per line, ignoring a trailing \r.
+// Blank lines are skipped; a row whose width differs from the first is an error.
+// Time Complexity: O(n) where n is the number of cells
+// Space Complexity: O(n) for the rows
+func Grid(r io.Reader) ([][]byte, error) {
+	var rows [][]byte
+
+	scanner := NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		row := bytes.TrimSuffix(scanner.Bytes(), []byte{'\r'})
+		if line == 1 {
+			row = TrimBOM(row)
+		}
+		if len(row) == 0 {
+			continue
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("line %d: ragged row: width %d, expected %d", line, len(row), len(rows[0]))
+		}
+
+		// The scanner reuses its buffer, so each row needs its own copy
+		rows = append(rows, bytes.Clone(row))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, ScanError(err, line+1)
+	}
+	return rows, nil
+}
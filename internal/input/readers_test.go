@@ -0,0 +1,55 @@
+package input
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTwoColumns(t *testing.T) {
+	left, right, err := TwoColumns(strings.NewReader("3   4\n\n4\t3\n  \n"))
+	if err != nil {
+		t.Fatalf("TwoColumns: %v", err)
+	}
+	if !slices.Equal(left, []int64{3, 4}) || !slices.Equal(right, []int64{4, 3}) {
+		t.Errorf("TwoColumns = %v, %v, want [3 4], [4 3]", left, right)
+	}
+
+	for input, want := range map[string]string{
+		"1 2\n3\n":     "line 2: expected 2 fields, got 1",
+		"1 2\n3 4 5\n": "line 2: expected 2 fields, got 3",
+		"1 x\n":        `line 1: invalid number: "x"`,
+	} {
+		if _, _, err := TwoColumns(strings.NewReader(input)); err == nil || err.Error() != want {
+			t.Errorf("TwoColumns(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestSingleColumn(t *testing.T) {
+	values, err := SingleColumn(strings.NewReader("\uFEFF7\n\n  -2  \n\n"))
+	if err != nil {
+		t.Fatalf("SingleColumn: %v", err)
+	}
+	if !slices.Equal(values, []int64{7, -2}) {
+		t.Errorf("SingleColumn = %v, want [7 -2]", values)
+	}
+
+	if _, err := SingleColumn(strings.NewReader("1\n2 3\n")); err == nil || err.Error() != `line 2: invalid number: "2 3"` {
+		t.Errorf("SingleColumn error = %v, want line 2 rejected", err)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	rows, err := Grid(strings.NewReader("#.#\r\n\n.#.\n"))
+	if err != nil {
+		t.Fatalf("Grid: %v", err)
+	}
+	if len(rows) != 2 || string(rows[0]) != "#.#" || string(rows[1]) != ".#." {
+		t.Errorf("Grid = %q, want [#.# .#.]", rows)
+	}
+
+	if _, err := Grid(strings.NewReader("###\n##\n")); err == nil || err.Error() != "line 2: ragged row: width 2, expected 3" {
+		t.Errorf("Grid error = %v, want the ragged row rejected", err)
+	}
+}
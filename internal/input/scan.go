@@ -0,0 +1,137 @@
+// Package input holds the line scanning and integer parsing shared by the
+// per-day solvers, along with generic readers for common puzzle input shapes.
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+const (
+	// InitialBufferSize is the scanner buffer allocated up front.
+	InitialBufferSize = 64 * 1024
+	// MaxLineSize caps how far the scanner buffer may grow for a single line.
+	MaxLineSize = 16 * 1024 * 1024
+)
+
+// NewScanner returns a line scanner whose buffer starts at 64KB and grows on demand
+// up to MaxLineSize.
+func NewScanner(r io.Reader) *bufio.Scanner {
+	// Use larger buffer size for potentially better IO performance
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, InitialBufferSize), MaxLineSize)
+	return scanner
+}
+
+// ScanError wraps a scanner failure, calling out lines too long to buffer.
+func ScanError(err error, line int) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("line %d is longer than the %d byte limit", line, MaxLineSize)
+	}
+	return fmt.Errorf("error reading input: %v", err)
+}
+
+// bom is the UTF-8 byte order mark some editors prepend to text files.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// TrimBOM strips a UTF-8 byte order mark from the start of line. Readers apply it
+// to the first line only.
+func TrimBOM(line []byte) []byte {
+	return bytes.TrimPrefix(line, bom)
+}
+
+// AppendFields appends the whitespace-separated fields of line to dst, behaving
+// like strings.Fields for ASCII whitespace. The fields alias line.
+func AppendFields(dst [][]byte, line []byte) [][]byte {
+	for i := 0; i < len(line); {
+		for i < len(line) && IsSpace(line[i]) {
+			i++
+		}
+		start := i
+		for i < len(line) && !IsSpace(line[i]) {
+			i++
+		}
+		if i > start {
+			dst = append(dst, line[start:i])
+		}
+	}
+	return dst
+}
+
+// IsSpace reports whether c is ASCII whitespace.
+func IsSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// ParseInt parses a base-10 integer with an optional sign directly from bytes,
+// accepting exactly what strconv.ParseInt(s, 10, 64) accepts. One leading '+' or
+// '-' is allowed, so "+42" is 42; a doubled sign, a base prefix such as 0x or
+// digit separators are rejected, and leading zeros never imply octal.
+func ParseInt(b []byte) (int64, bool) {
+	neg := false
+	if len(b) > 0 && (b[0] == '+' || b[0] == '-') {
+		neg = b[0] == '-'
+		b = b[1:]
+	}
+	if len(b) == 0 {
+		return 0, false
+	}
+
+	// The magnitude of math.MinInt64 is one larger than math.MaxInt64
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+
+	var n uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if n > (limit-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+
+	if neg {
+		return -int64(n), true
+	}
+	return int64(n), true
+}
+
+// ParseIntBase parses an integer with an optional sign in base 2, 8, 10 or 16. Base
+// 10 is ParseInt; base 16 also accepts a 0x or 0X prefix after the sign, so "0x1a"
+// and "-0X1A" parse. Other bases take bare digits as strconv.ParseInt does.
+func ParseIntBase(b []byte, base int) (int64, bool) {
+	if base == 10 {
+		return ParseInt(b)
+	}
+	s := string(b)
+	if base == 16 {
+		sign := ""
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			sign, s = s[:1], s[1:]
+		}
+		if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+			s = s[2:]
+			// The sign goes before the prefix, never after it
+			if s[0] == '+' || s[0] == '-' {
+				return 0, false
+			}
+		}
+		s = sign + s
+	}
+	n, err := strconv.ParseInt(s, base, 64)
+	return n, err == nil
+}
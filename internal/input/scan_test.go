@@ -0,0 +1,77 @@
+package input
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestParseInt(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{in: "42", want: 42, ok: true},
+		{in: "+42", want: 42, ok: true},
+		{in: "-42", want: -42, ok: true},
+		{in: "+0", want: 0, ok: true},
+		{in: "-0", want: 0, ok: true},
+		{in: "0042", want: 42, ok: true},
+		{in: "9223372036854775807", want: 9223372036854775807, ok: true},
+		{in: "-9223372036854775808", want: -9223372036854775808, ok: true},
+		{in: "9223372036854775808"},
+		{in: "++42"},
+		{in: "+-42"},
+		{in: "+"},
+		{in: "-"},
+		{in: ""},
+		{in: "4+2"},
+		{in: " 42"},
+		{in: "0x2a"},
+		{in: "1_000"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseInt([]byte(tt.in))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseInt(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+		// The byte parser accepts exactly what strconv does
+		want, err := strconv.ParseInt(tt.in, 10, 64)
+		if ok != (err == nil) || (ok && got != want) {
+			t.Errorf("ParseInt(%q) = %d, %v; strconv.ParseInt gives %d, %v", tt.in, got, ok, want, err)
+		}
+	}
+}
+
+func TestParseIntBase(t *testing.T) {
+	tests := []struct {
+		in   string
+		base int
+		want int64
+		ok   bool
+	}{
+		{in: "0x1a", base: 16, want: 26, ok: true},
+		{in: "0X1A", base: 16, want: 26, ok: true},
+		{in: "1a", base: 16, want: 26, ok: true},
+		{in: "-0x1a", base: 16, want: -26, ok: true},
+		{in: "+ff", base: 16, want: 255, ok: true},
+		{in: "0x", base: 16},
+		{in: "0x-1a", base: 16},
+		{in: "0x+1a", base: 16},
+		{in: "x1a", base: 16},
+		{in: "17", base: 8, want: 15, ok: true},
+		{in: "-17", base: 8, want: -15, ok: true},
+		{in: "8", base: 8},
+		{in: "0x17", base: 8},
+		{in: "101", base: 2, want: 5, ok: true},
+		{in: "102", base: 2},
+		{in: "26", base: 10, want: 26, ok: true},
+		{in: "1a", base: 10},
+	}
+	for _, tt := range tests {
+		got, ok := ParseIntBase([]byte(tt.in), tt.base)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseIntBase(%q, %d) = %d, %v, want %d, %v", tt.in, tt.base, got, ok, tt.want, tt.ok)
+		}
+	}
+}
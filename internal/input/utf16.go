@@ -0,0 +1,129 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UTF-16 byte order marks, as written by some Windows tools.
+var (
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// SniffUTF16 reports whether prefix, the first bytes of an input, starts with a
+// UTF-16 byte order mark, and if so whether the input is big-endian.
+func SniffUTF16(prefix []byte) (isUTF16, bigEndian bool) {
+	switch {
+	case len(prefix) >= 2 && prefix[0] == bomUTF16LE[0] && prefix[1] == bomUTF16LE[1]:
+		return true, false
+	case len(prefix) >= 2 && prefix[0] == bomUTF16BE[0] && prefix[1] == bomUTF16BE[1]:
+		return true, true
+	}
+	return false, false
+}
+
+// ErrTruncatedUTF16 reports UTF-16 input that ends halfway through a code unit.
+var ErrTruncatedUTF16 = errors.New("truncated UTF-16 input: odd number of bytes")
+
+// utf16Reader transcodes UTF-16 to UTF-8. Unpaired surrogates decode to U+FFFD.
+type utf16Reader struct {
+	r         *bufio.Reader
+	bigEndian bool
+	// pending holds encoded UTF-8 not yet returned by Read.
+	pending []byte
+	buf     [utf8.UTFMax]byte
+	// peeked holds a code unit read while looking for a low surrogate.
+	peeked    uint16
+	hasPeeked bool
+	// started is set once the leading byte order mark has been dropped.
+	started bool
+	err     error
+}
+
+// NewUTF16Reader returns a reader producing the UTF-8 encoding of r, which holds
+// UTF-16 of the given byte order. A leading byte order mark is dropped.
+func NewUTF16Reader(r io.Reader, bigEndian bool) io.Reader {
+	return &utf16Reader{r: bufio.NewReader(r), bigEndian: bigEndian}
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(u.pending) > 0 {
+			c := copy(p[n:], u.pending)
+			u.pending = u.pending[c:]
+			n += c
+			continue
+		}
+		if u.err != nil {
+			break
+		}
+		r, err := u.decode()
+		if err != nil {
+			u.err = err
+			continue
+		}
+		size := utf8.EncodeRune(u.buf[:], r)
+		u.pending = u.buf[:size]
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return 0, u.err
+}
+
+// decode returns the next rune, joining surrogate pairs.
+func (u *utf16Reader) decode() (rune, error) {
+	first, err := u.unit()
+	if err == nil && !u.started && first == 0xFEFF {
+		first, err = u.unit()
+	}
+	u.started = true
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(rune(first)) {
+		return rune(first), nil
+	}
+
+	second, err := u.unit()
+	if err != nil {
+		if err == io.EOF {
+			return utf8.RuneError, nil
+		}
+		return 0, err
+	}
+	r := utf16.DecodeRune(rune(first), rune(second))
+	if r == utf8.RuneError {
+		// Not a valid pair: the second unit starts the next rune
+		u.peeked, u.hasPeeked = second, true
+	}
+	return r, nil
+}
+
+// unit reads one code unit.
+func (u *utf16Reader) unit() (uint16, error) {
+	if u.hasPeeked {
+		u.hasPeeked = false
+		return u.peeked, nil
+	}
+	hi, err := u.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	lo, err := u.r.ReadByte()
+	if err == io.EOF {
+		return 0, ErrTruncatedUTF16
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !u.bigEndian {
+		hi, lo = lo, hi
+	}
+	return uint16(hi)<<8 | uint16(lo), nil
+}
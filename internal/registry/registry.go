@@ -0,0 +1,61 @@
+// Package registry maps each day to the solvers for its two puzzle parts.
+package registry
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Solver computes the answer for one part of a day's puzzle from its input.
+type Solver func(r io.Reader) (string, error)
+
+// Day is implemented by each day's package and registered from its init function.
+type Day interface {
+	// Number is the puzzle day, 1 through 25.
+	Number() int
+	Part1(r io.Reader) (string, error)
+	Part2(r io.Reader) (string, error)
+}
+
+var days = make(map[int]Day)
+
+// Register records d under its day number. It is meant to be called from a day
+// package's init function and panics on duplicates.
+func Register(d Day) {
+	n := d.Number()
+	if _, exists := days[n]; exists {
+		panic(fmt.Sprintf("registry: day %d registered twice", n))
+	}
+	days[n] = d
+}
+
+// Lookup returns the solver registered for the given day and part.
+func Lookup(day, part int) (Solver, bool) {
+	d, ok := days[day]
+	if !ok {
+		return nil, false
+	}
+	switch part {
+	case 1:
+		return d.Part1, true
+	case 2:
+		return d.Part2, true
+	}
+	return nil, false
+}
+
+// Days returns every registered day, in day order.
+func Days() []Day {
+	numbers := make([]int, 0, len(days))
+	for n := range days {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	all := make([]Day, len(numbers))
+	for i, n := range numbers {
+		all[i] = days[n]
+	}
+	return all
+}
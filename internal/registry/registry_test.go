@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeDay answers each part with the part's name.
+type fakeDay int
+
+func (d fakeDay) Number() int { return int(d) }
+
+func (d fakeDay) Part1(io.Reader) (string, error) { return "part 1", nil }
+
+func (d fakeDay) Part2(io.Reader) (string, error) { return "part 2", nil }
+
+func TestRegister(t *testing.T) {
+	Register(fakeDay(25))
+	Register(fakeDay(3))
+	t.Cleanup(func() {
+		delete(days, 25)
+		delete(days, 3)
+	})
+
+	for part, want := range map[int]string{1: "part 1", 2: "part 2"} {
+		solve, ok := Lookup(25, part)
+		if !ok {
+			t.Fatalf("Lookup(25, %d) found nothing", part)
+		}
+		if got, err := solve(strings.NewReader("")); err != nil || got != want {
+			t.Errorf("day 25 part %d = %q, %v, want %q", part, got, err, want)
+		}
+	}
+	if _, ok := Lookup(25, 3); ok {
+		t.Error("Lookup(25, 3) found a solver for a part that does not exist")
+	}
+	if _, ok := Lookup(24, 1); ok {
+		t.Error("Lookup(24, 1) found a solver for an unregistered day")
+	}
+
+	all := Days()
+	if len(all) != 2 || all[0].Number() != 3 || all[1].Number() != 25 {
+		t.Errorf("Days = %v, want days 3 and 25 in order", all)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	Register(fakeDay(7))
+	t.Cleanup(func() { delete(days, 7) })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering day 7 twice did not panic")
+		}
+	}()
+	Register(fakeDay(7))
+}
@@ -0,0 +1,91 @@
+// Package runner solves several days concurrently on a bounded pool of workers.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"aoc-2024/internal/registry"
+)
+
+// Result holds the answers for one day, or the error that stopped its solve.
+type Result struct {
+	Day          int
+	Part1, Part2 string
+	Err          error
+}
+
+// RunAll solves both parts of every day on at most workers goroutines and returns
+// the results in the order of days. input is called once per part, since solving
+// consumes the reader; a returned io.Closer is closed after the part is solved.
+// Days not started before ctx is done report ctx.Err().
+func RunAll(ctx context.Context, days []registry.Day, input func(registry.Day) io.Reader, workers int) []Result {
+	results := make([]Result, len(days))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = solve(days[i], input)
+			}
+		}()
+	}
+
+	for i, d := range days {
+		if ctx.Err() == nil {
+			select {
+			case indices <- i:
+				continue
+			case <-ctx.Done():
+			}
+		}
+		results[i] = Result{Day: d.Number(), Err: fmt.Errorf("day %d: %w", d.Number(), ctx.Err())}
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
+
+// solve runs both parts of d, stopping at the first error.
+func solve(d registry.Day, input func(registry.Day) io.Reader) Result {
+	res := Result{Day: d.Number()}
+	for part, solver := range []registry.Solver{d.Part1, d.Part2} {
+		answer, err := solvePart(d, solver, input)
+		if err != nil {
+			res.Err = fmt.Errorf("day %d part %d: %w", res.Day, part+1, err)
+			return res
+		}
+		if part == 0 {
+			res.Part1 = answer
+		} else {
+			res.Part2 = answer
+		}
+	}
+	return res
+}
+
+// solvePart runs solver on a fresh input for d, closing it afterwards.
+func solvePart(d registry.Day, solver registry.Solver, input func(registry.Day) io.Reader) (string, error) {
+	r := input(d)
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	return solver(r)
+}
+
+// Err joins the errors of the failed days, in day order, or returns nil when
+// every day was solved.
+func Err(results []Result) error {
+	var errs []error
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aoc-2024/internal/registry"
+)
+
+// fakeDay answers both parts with its input after a pause, tracking how many
+// days are solving at once, or fails part 2 with err.
+type fakeDay struct {
+	number        int
+	pause         time.Duration
+	err           error
+	running, peak *atomic.Int32
+}
+
+func (d fakeDay) Number() int { return d.number }
+
+func (d fakeDay) Part1(r io.Reader) (string, error) { return d.answer(r) }
+
+func (d fakeDay) Part2(r io.Reader) (string, error) {
+	if d.err != nil {
+		return "", d.err
+	}
+	return d.answer(r)
+}
+
+func (d fakeDay) answer(r io.Reader) (string, error) {
+	n := d.running.Add(1)
+	defer d.running.Add(-1)
+	for {
+		if peak := d.peak.Load(); n <= peak || d.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(d.pause)
+	b, err := io.ReadAll(r)
+	return string(b), err
+}
+
+// closingReader counts how often it is closed.
+type closingReader struct {
+	io.Reader
+	closes *atomic.Int32
+}
+
+func (r closingReader) Close() error {
+	r.closes.Add(1)
+	return nil
+}
+
+func TestRunAll(t *testing.T) {
+	var running, peak, closes atomic.Int32
+	errBroken := errors.New("broken")
+	var days []registry.Day
+	for n := 1; n <= 8; n++ {
+		d := fakeDay{number: n, running: &running, peak: &peak}
+		// Later days finish first, so results arrive out of order
+		d.pause = time.Duration(9-n) * 5 * time.Millisecond
+		if n == 2 || n == 5 {
+			d.err = errBroken
+		}
+		days = append(days, d)
+	}
+	input := func(d registry.Day) io.Reader {
+		return closingReader{strings.NewReader(fmt.Sprint("day ", d.Number())), &closes}
+	}
+
+	results := RunAll(context.Background(), days, input, 3)
+	if len(results) != len(days) {
+		t.Fatalf("RunAll returned %d results for %d days", len(results), len(days))
+	}
+	for i, res := range results {
+		if res.Day != i+1 {
+			t.Errorf("results[%d] is day %d, want day order", i, res.Day)
+		}
+		failed := res.Day == 2 || res.Day == 5
+		if failed != errors.Is(res.Err, errBroken) {
+			t.Errorf("day %d error = %v", res.Day, res.Err)
+		}
+		if want := fmt.Sprint("day ", res.Day); !failed && (res.Part1 != want || res.Part2 != want) {
+			t.Errorf("day %d answers = %q, %q, want %q", res.Day, res.Part1, res.Part2, want)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d days solved at once, want at most 3 workers", p)
+	}
+	// Every input is closed, including the failed part 2s
+	if n := closes.Load(); n != 2*int32(len(days)) {
+		t.Errorf("inputs closed %d times, want %d", n, 2*len(days))
+	}
+
+	err := Err(results)
+	if !errors.Is(err, errBroken) || !strings.Contains(err.Error(), "day 2 part 2") || !strings.Contains(err.Error(), "day 5 part 2") {
+		t.Errorf("Err = %v, want the failures of days 2 and 5", err)
+	}
+	if strings.Index(err.Error(), "day 2") > strings.Index(err.Error(), "day 5") {
+		t.Errorf("Err = %v, want the failures in day order", err)
+	}
+	if err := Err(results[:1]); err != nil {
+		t.Errorf("Err of a solved day = %v, want nil", err)
+	}
+}
+
+func TestRunAllCancelled(t *testing.T) {
+	var running, peak atomic.Int32
+	days := []registry.Day{fakeDay{number: 1, running: &running, peak: &peak}, fakeDay{number: 2, running: &running, peak: &peak}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := func(registry.Day) io.Reader { return strings.NewReader("") }
+	for _, res := range RunAll(ctx, days, input, 1) {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("day %d error = %v, want context.Canceled", res.Day, res.Err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Package testutil holds helpers shared by the per-day test files.
+package testutil
+
+import (
+	"strings"
+	"testing"
+
+	"aoc-2024/internal/input"
+)
+
+// MustParseColumns parses a two-column string literal into its left and right
+// columns, failing the test on any malformed line.
+func MustParseColumns(t testing.TB, text string) (left, right []int64) {
+	t.Helper()
+
+	left, right, err := input.TwoColumns(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("MustParseColumns: %v", err)
+	}
+	return left, right
+}
@@ -0,0 +1,32 @@
+package testutil
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// recorder is a testing.TB that records a fatal failure instead of stopping.
+type recorder struct {
+	testing.TB
+	fatal string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestMustParseColumns(t *testing.T) {
+	left, right := MustParseColumns(t, "3   4\n\n4   3\n")
+	if !slices.Equal(left, []int64{3, 4}) || !slices.Equal(right, []int64{4, 3}) {
+		t.Errorf("MustParseColumns = %v, %v, want [3 4], [4 3]", left, right)
+	}
+
+	rec := &recorder{TB: t}
+	MustParseColumns(rec, "3 4\n5\n")
+	if rec.fatal != "MustParseColumns: line 2: expected 2 fields, got 1" {
+		t.Errorf("fatal message = %q", rec.fatal)
+	}
+}